@@ -0,0 +1,88 @@
+package digpro
+
+import (
+	"go.uber.org/dig"
+)
+
+// registration is a successful call of Provide, Remove or RemoveGroupMembers, which will be replayed by Clone
+type registration struct {
+	info        int // index of provideInfos, only for Provide
+	constructor interface{}
+	opts        []dig.ProvideOption
+	// rebuild the constructor for the cloned container, if the constructor depends on the container (e.g. Struct)
+	rebuild func(c *ContainerWrapper) interface{}
+	// call is the method not Provide (e.g. Remove), replay it instead of Provide
+	call func(c *ContainerWrapper) error
+}
+
+// recordProvide record the provider registered just now
+func (c *ContainerWrapper) recordProvide(constructor interface{}, opts []dig.ProvideOption) {
+	c.registrations = append(c.registrations, registration{
+		info:        len(c.provideInfos) - 1,
+		constructor: constructor,
+		opts:        append([]dig.ProvideOption{}, opts...),
+	})
+}
+
+// recordCall record a successful call of the method which changes the providers (e.g. Remove)
+func (c *ContainerWrapper) recordCall(call func(c *ContainerWrapper) error) {
+	c.registrations = append(c.registrations, registration{info: -1, call: call})
+}
+
+// setLastRegistrationRebuild set the rebuild function of the provider registered just now, see registration
+func (c *ContainerWrapper) setLastRegistrationRebuild(rebuild func(c *ContainerWrapper) interface{}) {
+	if len(c.registrations) != 0 {
+		c.registrations[len(c.registrations)-1].rebuild = rebuild
+	}
+}
+
+// Clone make a new container, and replay all provider registrations of c in order
+// (include Provide, Struct, Supply, Override, Remove and so on).
+// The constructors will be called again in the new container, so no value constructed by c is shared
+// (except the values passed to Supply), and the providers registered to the new container (e.g. Override)
+// will not change c. The lifecycle hooks are not cloned, they are registered when the constructors are called.
+//
+// If c is a child container (see Scope), the parent will be cloned too.
+//
+// for example
+//   c := digpro.New()
+//   _ = c.Provide(func() int { fmt.Println("called"); return 1 }) // please handle error in production
+//   clone, _ := c.Clone()
+//   _ = clone.Supply(2, digpro.Override())
+//   i1, _ := c.Extract(int(0))
+//   i2, _ := clone.Extract(int(0))
+//   fmt.Println(i1, i2)
+//   // Output:
+//   // called
+//   // 1 2
+func (c *ContainerWrapper) Clone() (*ContainerWrapper, error) {
+	c.syncParent()
+	clone := New(c.digOptions...)
+	if c.parent != nil {
+		parent, err := c.parent.Clone()
+		if err != nil {
+			return nil, err
+		}
+		clone = parent.Scope(c.name)
+		clone.scopePC = c.scopePC
+	}
+	for _, r := range c.registrations {
+		if r.call != nil {
+			if err := r.call(clone); err != nil {
+				return nil, wrapError("Clone", err)
+			}
+			continue
+		}
+		constructor := r.constructor
+		if r.rebuild != nil {
+			constructor = r.rebuild(clone)
+		}
+		if err := clone.Provide(constructor, r.opts...); err != nil {
+			return nil, wrapError("Clone", err)
+		}
+		clone.setLastProviderKind(c.provideInfos[r.info].Kind)
+		clone.setLastRegistrationRebuild(r.rebuild)
+	}
+	clone.existResolveCyclicOption = c.existResolveCyclicOption
+	return clone, nil
+}
@@ -0,0 +1,20 @@
+package digpro_test
+
+import (
+	"fmt"
+
+	"github.com/rectcircle/digpro"
+)
+
+func ExampleContainerWrapper_Clone() {
+	c := digpro.New()
+	_ = c.Provide(func() int { fmt.Println("called"); return 1 }) // please handle error in production
+	clone, _ := c.Clone()
+	_ = clone.Supply(2, digpro.Override())
+	i1, _ := c.Extract(int(0))
+	i2, _ := clone.Extract(int(0))
+	fmt.Println(i1, i2)
+	// Output:
+	// called
+	// 1 2
+}
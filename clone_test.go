@@ -0,0 +1,220 @@
+package digpro
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/dig"
+)
+
+type cloneDB struct {
+	Name string
+}
+
+type cloneService struct {
+	DB *cloneDB
+}
+
+func TestContainerWrapper_Clone(t *testing.T) {
+	type args struct {
+		prepare PrepareFunc
+		mutate  func(clone *ContainerWrapper) error
+		extract func(c *ContainerWrapper) (interface{}, error)
+	}
+	tests := []struct {
+		name       string
+		args       args
+		scope      bool
+		want       interface{}
+		wantOrigin interface{}
+	}{
+		{
+			name: "override transitive dependency",
+			args: args{
+				prepare: func(c *ContainerWrapper) error {
+					return firstError(
+						c.Provide(func() *cloneDB { return &cloneDB{Name: "real"} }),
+						c.Struct(new(cloneService)),
+					)
+				},
+				mutate: func(clone *ContainerWrapper) error {
+					return clone.Provide(func() *cloneDB { return &cloneDB{Name: "mock"} }, Override())
+				},
+				extract: func(c *ContainerWrapper) (interface{}, error) {
+					svc, err := c.Extract(new(cloneService))
+					if err != nil {
+						return nil, err
+					}
+					return svc.(*cloneService).DB.Name, nil
+				},
+			},
+			want:       "mock",
+			wantOrigin: "real",
+		},
+		{
+			name: "replay override and remove",
+			args: args{
+				prepare: func(c *ContainerWrapper) error {
+					return firstError(
+						c.Supply(1),
+						c.Supply(2, Override()),
+						c.Supply("a"),
+						c.Remove(""),
+						c.Supply("b"),
+						c.Supply("x", dig.Group("g")),
+						c.Supply("y", dig.Group("g"), GroupPriority(1)),
+						c.RemoveGroupMembers("", "g"),
+						c.Supply("z", dig.Group("g")),
+					)
+				},
+				extract: func(c *ContainerWrapper) (interface{}, error) {
+					return c.Extract(struct {
+						dig.In
+						I int
+						S string
+						G []string `group:"g"`
+					}{})
+				},
+			},
+			want: struct {
+				dig.In
+				I int
+				S string
+				G []string `group:"g"`
+			}{I: 2, S: "b", G: []string{"z"}},
+		},
+		{
+			name: "struct default by clone providers",
+			args: args{
+				prepare: func(c *ContainerWrapper) error {
+					return c.Struct(DefaultFoo{})
+				},
+				mutate: func(clone *ContainerWrapper) error {
+					return clone.Supply(2)
+				},
+				extract: func(c *ContainerWrapper) (interface{}, error) {
+					foo, err := c.Extract(DefaultFoo{})
+					if err != nil {
+						return nil, err
+					}
+					return foo.(DefaultFoo).A, nil
+				},
+			},
+			want:       2,
+			wantOrigin: 1,
+		},
+		{
+			name: "scope",
+			args: args{
+				prepare: func(c *ContainerWrapper) error {
+					return firstError(
+						c.parent.Provide(func() *cloneDB { return &cloneDB{Name: "real"} }),
+						c.Struct(new(cloneService)),
+					)
+				},
+				mutate: func(clone *ContainerWrapper) error {
+					return clone.parent.Provide(func() *cloneDB { return &cloneDB{Name: "mock"} }, Override())
+				},
+				extract: func(c *ContainerWrapper) (interface{}, error) {
+					svc, err := c.Extract(new(cloneService))
+					if err != nil {
+						return nil, err
+					}
+					return svc.(*cloneService).DB.Name, nil
+				},
+			},
+			scope:      true,
+			want:       "mock",
+			wantOrigin: "real",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+			if tt.scope {
+				c = c.Scope("child")
+			}
+			if err := tt.args.prepare(c); err != nil {
+				t.Errorf("prepare() error = %v", err)
+				return
+			}
+			clone, err := c.Clone()
+			if err != nil {
+				t.Errorf("c.Clone() error = %v", err)
+				return
+			}
+			if tt.args.mutate != nil {
+				if err := tt.args.mutate(clone); err != nil {
+					t.Errorf("mutate() error = %v", err)
+					return
+				}
+			}
+			got, err := tt.args.extract(clone)
+			if err != nil {
+				t.Errorf("extract(clone) error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("extract(clone) = %#v, want %#v", got, tt.want)
+			}
+			wantOrigin := tt.wantOrigin
+			if wantOrigin == nil {
+				wantOrigin = tt.want
+			}
+			got, err = tt.args.extract(c)
+			if err != nil {
+				t.Errorf("extract(c) error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, wantOrigin) {
+				t.Errorf("extract(c) = %#v, want %#v", got, wantOrigin)
+			}
+		})
+	}
+}
+
+func TestContainerWrapper_Clone_providers(t *testing.T) {
+	c := New(dig.DeferAcyclicVerification())
+	called := 0
+	if err := firstError(
+		c.Provide(func() time.Duration { called++; return time.Second }),
+		c.Supply(1, LifecycleHook(Hook{})),
+		c.Struct(new(DefaultFoo)),
+		c.Struct(new(validateA), ResolveCyclic()),
+		c.Struct(new(validateB)),
+		c.Provide(func(a *validateA) validateC { return validateC{A: a} }),
+	); err != nil {
+		t.Errorf("prepare error = %v", err)
+		return
+	}
+	if _, err := c.Extract(time.Duration(0)); err != nil {
+		t.Errorf("c.Extract() error = %v", err)
+		return
+	}
+	clone, err := c.Clone()
+	if err != nil {
+		t.Errorf("c.Clone() error = %v", err)
+		return
+	}
+	if diff := DiffGraphs(c, clone); !diff.Empty() {
+		t.Errorf("DiffGraphs() = %s, want empty", diff.String())
+	}
+	if _, err := clone.Extract(time.Duration(0)); err != nil || called != 2 {
+		t.Errorf("clone.Extract() error = %v, called = %d, want constructor called again", err, called)
+	}
+	a, err := clone.Extract(new(validateA))
+	if err != nil || a.(*validateA).B.C.A != a {
+		t.Errorf("clone.Extract() = %v, %v, want cyclic resolved", a, err)
+	}
+	// the clone of clone
+	if _, err := clone.Clone(); err != nil {
+		t.Errorf("clone.Clone() error = %v", err)
+	}
+	// the provider kinds and locations are kept
+	for i, p := range clone.Providers() {
+		if want := c.Providers()[i]; p.Kind != want.Kind || p.Location.String() != want.Location.String() {
+			t.Errorf("clone.Providers()[%d] = %s at %v, want %s at %v", i, p.Kind, p.Location, want.Kind, want.Location)
+		}
+	}
+}
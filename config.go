@@ -0,0 +1,226 @@
+package digpro
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/rectcircle/digpro/internal"
+	"go.uber.org/dig"
+)
+
+type configValue struct {
+	name  string
+	value reflect.Value
+}
+
+// normalizeConfigDocument convert map[interface{}]interface{} (decoded by yaml) to map[string]interface{}
+func normalizeConfigDocument(document interface{}) interface{} {
+	switch d := document.(type) {
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(d))
+		for k, v := range d {
+			result[fmt.Sprint(k)] = normalizeConfigDocument(v)
+		}
+		return result
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(d))
+		for k, v := range d {
+			result[k] = normalizeConfigDocument(v)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, 0, len(d))
+		for _, v := range d {
+			result = append(result, normalizeConfigDocument(v))
+		}
+		return result
+	default:
+		return document
+	}
+}
+
+// decodeConfig decode document to a new value as same type as structOrStructPtr
+func decodeConfig(document interface{}, structOrStructPtr interface{}) (reflect.Value, error) {
+	isPtr, structTyp, err := structTypeOf(structOrStructPtr)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if dig.IsOut(structTyp) || dig.IsIn(structTyp) {
+		return reflect.Value{}, fmt.Errorf("structOrStructPtr should not embed dig.Out or dig.In, but got %s", structTyp)
+	}
+	var data []byte
+	switch d := document.(type) {
+	case []byte:
+		data = d
+	case string:
+		data = []byte(d)
+	default:
+		data, err = json.Marshal(normalizeConfigDocument(document))
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("document want map[string]interface{} or json bytes, marshal error: %w", err)
+		}
+	}
+	_, structPtrValue, _ := structPtrValueOf(structOrStructPtr)
+	value := reflect.New(structTyp)
+	value.Elem().Set(structPtrValue.Elem())
+	if err := json.Unmarshal(data, value.Interface()); err != nil {
+		return reflect.Value{}, fmt.Errorf("decode document to %s error: %w", structTyp, err)
+	}
+	if isPtr {
+		return value, nil
+	}
+	return value.Elem(), nil
+}
+
+func joinConfigName(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// configFieldName return name of field in config document, return false if the field should be skipped
+func configFieldName(f reflect.StructField) (string, bool) {
+	if f.PkgPath != "" {
+		return "", false
+	}
+	name := strings.Split(f.Tag.Get("json"), ",")[0]
+	if name == "-" {
+		return "", false
+	}
+	if name == "" {
+		name = strings.ToLower(f.Name)
+	}
+	return name, true
+}
+
+// collectConfigValues collect all sub structs and leaves of structValue
+func collectConfigValues(prefix string, structValue reflect.Value) []configValue {
+	result := []configValue{}
+	for i := 0; i < structValue.NumField(); i++ {
+		f := structValue.Type().Field(i)
+		name, ok := configFieldName(f)
+		if !ok {
+			continue
+		}
+		fieldValue := structValue.Field(i)
+		fieldStructValue := fieldValue
+		if fieldValue.Kind() == reflect.Ptr && fieldValue.Type().Elem().Kind() == reflect.Struct {
+			fieldStructValue = reflect.New(fieldValue.Type().Elem()).Elem()
+			if !fieldValue.IsNil() {
+				fieldStructValue = fieldValue.Elem()
+			}
+		}
+		if fieldStructValue.Kind() != reflect.Struct || fieldStructValue.Type() == internal.TimeType {
+			result = append(result, configValue{name: joinConfigName(prefix, name), value: fieldValue})
+			continue
+		}
+		// json flatten anonymous struct fields
+		if f.Anonymous && f.Tag.Get("json") == "" {
+			result = append(result, collectConfigValues(prefix, fieldStructValue)...)
+			continue
+		}
+		result = append(result, configValue{name: joinConfigName(prefix, name), value: fieldValue})
+		result = append(result, collectConfigValues(joinConfigName(prefix, name), fieldStructValue)...)
+	}
+	return result
+}
+
+// Config make a constructor, which decode the document to the type of structOrStructPtr,
+// and provide the decoded value, every sub struct and every leaf as named value.
+// The name is prefix and field names (json tag or lower case field name) joined by dot,
+// and the decoded value is named by prefix (unnamed if prefix is empty).
+//
+// document support map[string]interface{}, map[interface{}]interface{} (decoded by yaml) and json []byte or string.
+//
+// for example
+//   type Config struct {
+//   	DB struct {
+//   		DSN string `json:"dsn"`
+//   	} `json:"db"`
+//   }
+//   c := dig.New()
+//   _ = c.Provide(digpro.Config("config", map[string]interface{}{ // please handle error in production
+//   	"db": map[string]interface{}{"dsn": "this is db dsn"},
+//   }, Config{}))
+//   // equals to
+//   // c.Provide(func() (struct {
+//   // 	dig.Out
+//   // 	Config Config                          `name:"config"`
+//   // 	DB     struct{ DSN string `json:"dsn"` } `name:"config.db"`
+//   // 	DB_DSN string                          `name:"config.db.dsn"`
+//   // }) { ... })
+//   dsn, _ := digpro.Extract(c, "", digpro.ExtractByName("config.db.dsn"))
+//   fmt.Println(dsn)
+//   // Output: this is db dsn
+func Config(prefix string, document interface{}, structOrStructPtr interface{}) interface{} {
+	value, err := decodeConfig(document, structOrStructPtr)
+	if err != nil {
+		return wrapError("Config", err)
+	}
+	values := []configValue{{name: prefix, value: value}}
+	values = append(values, collectConfigValues(prefix, underlyingValue(value))...)
+
+	// make result object type
+	fields := []reflect.StructField{internal.DigOutField}
+	results := []reflect.Value{}
+	for i, v := range values {
+		f := reflect.StructField{
+			Name: fmt.Sprintf("Value%d", i),
+			Type: v.value.Type(),
+		}
+		if v.name != "" {
+			f.Tag = reflect.StructTag(fmt.Sprintf(`%s:"%s"`, internal.DigNameTag, v.name))
+		}
+		fields = append(fields, f)
+		results = append(results, v.value)
+	}
+	resultObjectType := reflect.StructOf(fields)
+	resultObject := reflect.New(resultObjectType).Elem()
+	for i, result := range results {
+		resultObject.Field(i + 1).Set(result)
+	}
+
+	ft := reflect.FuncOf([]reflect.Type{}, []reflect.Type{resultObjectType}, false)
+	fv := reflect.MakeFunc(ft, func([]reflect.Value) []reflect.Value {
+		return []reflect.Value{resultObject}
+	})
+	return fv.Interface()
+}
+
+// Config decode the document to the type of structOrStructPtr,
+// and provide the decoded value, every sub struct and every leaf as named value.
+// The name is prefix and field names (json tag or lower case field name) joined by dot,
+// and the decoded value is named by prefix (unnamed if prefix is empty).
+//
+// document support map[string]interface{}, map[interface{}]interface{} (decoded by yaml) and json []byte or string.
+//
+// for example
+//   type Config struct {
+//   	DB struct {
+//   		DSN string `json:"dsn"`
+//   	} `json:"db"`
+//   }
+//   type DB struct {
+//   	DSN string `name:"config.db.dsn"`
+//   }
+//   c := digpro.New()
+//   digpro.QuickPanic(
+//   	c.Config("config", []byte(`{"db": {"dsn": "this is db dsn"}}`), Config{}),
+//   	c.Struct(new(DB)),
+//   )
+//   db, _ := c.Extract(new(DB))
+//   fmt.Println(db.(*DB).DSN)
+//   // Output: this is db dsn
+func (c *ContainerWrapper) Config(prefix string, document interface{}, structOrStructPtr interface{}, opts ...dig.ProvideOption) error {
+	filteredOpts, digproOptsResult := filterProvideOptionAndGetDigproOptions(opts, locationFixOptionType)
+	locationFix := digproOptsResult.locationFix(3)
+	err := internal.ProvideWithLocationFix(c.Provide, locationFix, Config(prefix, document, structOrStructPtr), filteredOpts...)
+	if err != nil {
+		return err
+	}
+	c.setLastProviderKind(internal.ProviderKindSupply)
+	return nil
+}
@@ -0,0 +1,39 @@
+package digpro_test
+
+import (
+	"fmt"
+
+	"github.com/rectcircle/digpro"
+	"go.uber.org/dig"
+)
+
+type AppConfig struct {
+	DB struct {
+		DSN string `json:"dsn"`
+	} `json:"db"`
+}
+
+type AppDB struct {
+	DSN string `name:"config.db.dsn"`
+}
+
+func ExampleConfig() {
+	c := dig.New()
+	_ = c.Provide(digpro.Config("config", map[string]interface{}{ // please handle error in production
+		"db": map[string]interface{}{"dsn": "this is db dsn"},
+	}, AppConfig{}))
+	dsn, _ := digpro.Extract(c, "", digpro.ExtractByName("config.db.dsn"))
+	fmt.Println(dsn)
+	// Output: this is db dsn
+}
+
+func ExampleContainerWrapper_Config() {
+	c := digpro.New()
+	digpro.QuickPanic(
+		c.Config("config", []byte(`{"db": {"dsn": "this is db dsn"}}`), AppConfig{}),
+		c.Struct(new(AppDB)),
+	)
+	db, _ := c.Extract(new(AppDB))
+	fmt.Println(db.(*AppDB).DSN)
+	// Output: this is db dsn
+}
@@ -0,0 +1,212 @@
+package digpro
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/dig"
+)
+
+type testConfigDB struct {
+	DSN     string        `json:"dsn"`
+	Timeout time.Duration `json:"timeout"`
+}
+
+type testConfig struct {
+	DB      testConfigDB `json:"db"`
+	Cache   *struct{ Addr string }
+	Name    string
+	Ignore  string `json:"-"`
+	private string //lint:ignore U1000 for test
+	Created time.Time
+	Tags    []string
+}
+
+type testConfigArgs struct {
+	prefix            string
+	document          interface{}
+	structOrStructPtr interface{}
+}
+
+type testConfigWant struct {
+	typ   interface{}
+	name  string
+	value interface{}
+}
+
+var testConfigData = []struct {
+	name           string
+	args           testConfigArgs
+	wantErr        bool
+	wantErrContain string
+	want           []testConfigWant
+}{
+	{
+		name: "error not struct",
+		args: testConfigArgs{
+			prefix:            "config",
+			document:          map[string]interface{}{},
+			structOrStructPtr: 1,
+		},
+		wantErr:        true,
+		wantErrContain: "[Config]",
+	},
+	{
+		name: "error dig.Out",
+		args: testConfigArgs{
+			prefix:   "config",
+			document: map[string]interface{}{},
+			structOrStructPtr: struct {
+				dig.Out
+				A string `name:"a"`
+			}{},
+		},
+		wantErr:        true,
+		wantErrContain: "should not embed dig.Out or dig.In",
+	},
+	{
+		name: "error document type",
+		args: testConfigArgs{
+			prefix:            "config",
+			document:          map[string]interface{}{"name": func() {}},
+			structOrStructPtr: testConfig{},
+		},
+		wantErr:        true,
+		wantErrContain: "marshal error",
+	},
+	{
+		name: "error decode",
+		args: testConfigArgs{
+			prefix:            "config",
+			document:          []byte(`{"name": 1}`),
+			structOrStructPtr: testConfig{},
+		},
+		wantErr:        true,
+		wantErrContain: "decode document to digpro.testConfig error",
+	},
+	{
+		name: "success map",
+		args: testConfigArgs{
+			prefix: "config",
+			document: map[string]interface{}{
+				"db": map[interface{}]interface{}{
+					"dsn":     "dsn",
+					"timeout": int64(time.Second),
+				},
+				"name":   "name",
+				"ignore": "ignore",
+				"tags":   []interface{}{"a", "b"},
+			},
+			structOrStructPtr: testConfig{Name: "default"},
+		},
+		wantErr: false,
+		want: []testConfigWant{
+			{typ: testConfig{}, name: "config", value: testConfig{
+				DB:   testConfigDB{DSN: "dsn", Timeout: time.Second},
+				Name: "name",
+				Tags: []string{"a", "b"},
+			}},
+			{typ: testConfigDB{}, name: "config.db", value: testConfigDB{DSN: "dsn", Timeout: time.Second}},
+			{typ: "", name: "config.db.dsn", value: "dsn"},
+			{typ: time.Duration(0), name: "config.db.timeout", value: time.Second},
+			{typ: (*struct{ Addr string })(nil), name: "config.cache", value: (*struct{ Addr string })(nil)},
+			{typ: "", name: "config.cache.addr", value: ""},
+			{typ: "", name: "config.name", value: "name"},
+			{typ: time.Time{}, name: "config.created", value: time.Time{}},
+			{typ: []string{}, name: "config.tags", value: []string{"a", "b"}},
+		},
+	},
+	{
+		name: "success json ptr without prefix",
+		args: testConfigArgs{
+			prefix:            "",
+			document:          `{"db": {"dsn": "dsn"}, "cache": {"addr": "addr"}}`,
+			structOrStructPtr: &testConfig{Name: "default"},
+		},
+		wantErr: false,
+		want: []testConfigWant{
+			{typ: &testConfig{}, value: &testConfig{
+				DB:    testConfigDB{DSN: "dsn"},
+				Cache: &struct{ Addr string }{Addr: "addr"},
+				Name:  "default",
+			}},
+			{typ: "", name: "db.dsn", value: "dsn"},
+			{typ: "", name: "cache.addr", value: "addr"},
+			{typ: "", name: "name", value: "default"},
+		},
+	},
+}
+
+func assertConfigWant(t *testing.T, extract func(typ interface{}, opts ...ExtractOption) (interface{}, error), wants []testConfigWant) {
+	for _, want := range wants {
+		got, err := extract(want.typ, ExtractByName(want.name))
+		if err != nil {
+			t.Errorf("Extract(%T, %q) error = %v", want.typ, want.name, err)
+			return
+		}
+		if !reflect.DeepEqual(got, want.value) {
+			t.Errorf("Extract(%T, %q) = %#v, want %#v", want.typ, want.name, got, want.value)
+		}
+	}
+}
+
+func TestConfig(t *testing.T) {
+	for _, tt := range testConfigData {
+		t.Run(tt.name, func(t *testing.T) {
+			c := dig.New()
+			err := c.Provide(Config(tt.args.prefix, tt.args.document, tt.args.structOrStructPtr))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("c.Provide(Config()) error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				if !strings.Contains(err.Error(), tt.wantErrContain) {
+					t.Errorf("c.Provide(Config()) error = %v, want contain = %s", err, tt.wantErrContain)
+				}
+				return
+			}
+			assertConfigWant(t, func(typ interface{}, opts ...ExtractOption) (interface{}, error) {
+				return Extract(c, typ, opts...)
+			}, tt.want)
+		})
+	}
+}
+
+func TestContainerWrapper_Config(t *testing.T) {
+	for _, tt := range testConfigData {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+			err := c.Config(tt.args.prefix, tt.args.document, tt.args.structOrStructPtr)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("c.Config() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				if !strings.Contains(err.Error(), tt.wantErrContain) {
+					t.Errorf("c.Config() error = %v, want contain = %s", err, tt.wantErrContain)
+				}
+				return
+			}
+			assertConfigWant(t, c.Extract, tt.want)
+		})
+	}
+}
+
+func TestContainerWrapper_Config_twoPrefixes(t *testing.T) {
+	c := New()
+	if err := firstError(
+		c.Config("primary", `{"dsn": "primary dsn"}`, testConfigDB{}),
+		c.Config("replica", `{"dsn": "replica dsn"}`, testConfigDB{}),
+	); err != nil {
+		t.Errorf("c.Config() error = %v", err)
+		return
+	}
+	assertConfigWant(t, c.Extract, []testConfigWant{
+		{typ: testConfigDB{}, name: "primary", value: testConfigDB{DSN: "primary dsn"}},
+		{typ: testConfigDB{}, name: "replica", value: testConfigDB{DSN: "replica dsn"}},
+		{typ: "", name: "primary.dsn", value: "primary dsn"},
+		{typ: "", name: "replica.dsn", value: "replica dsn"},
+	})
+}
@@ -0,0 +1,253 @@
+package digpro
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// GraphDiff is the difference of dependency graphs between two containers, see DiffGraphs
+type GraphDiff struct {
+	// Added is the providers in b but not in a
+	Added []ProviderDescriptor
+	// Removed is the providers in a but not in b
+	Removed []ProviderDescriptor
+	// Changed is the providers with same outputs in a and b, but the inputs are changed
+	Changed []ProviderInputsChange
+	// NewOverrides is the providers in b which override other providers, but not in a
+	NewOverrides []ProviderDescriptor
+	// NewCycles is the cycles (include broken by digpro.ResolveCyclic() and digpro.Lazy) in b but not in a,
+	// every cycle is the providers of b in dependency order
+	NewCycles [][]ProviderDescriptor
+}
+
+// ProviderInputsChange is the inputs change of the provider with same outputs
+type ProviderInputsChange struct {
+	// Provider is the provider in b
+	Provider      ProviderDescriptor
+	AddedInputs   []ProvideInput
+	RemovedInputs []ProvideInput
+}
+
+// providerKey identify the provider between containers by outputs, the providers with same outputs
+// (e.g. value group members) are distinguished by registration order
+func providerKeys(providers []ProviderDescriptor) map[int]string {
+	keys := make(map[int]string, len(providers))
+	count := make(map[string]int)
+	for _, p := range providers {
+		outputs := make([]string, 0, len(p.Outputs))
+		for i := range p.Outputs {
+			outputs = append(outputs, p.Outputs[i].String())
+		}
+		sort.Strings(outputs)
+		key := strings.Join(outputs, ", ")
+		if n := count[key]; n != 0 {
+			count[key]++
+			key = fmt.Sprintf("%s#%d", key, n)
+		} else {
+			count[key] = 1
+		}
+		keys[p.ID] = key
+	}
+	return keys
+}
+
+// activeProviders return the providers which are not removed
+func (c *ContainerWrapper) activeProviders() []ProviderDescriptor {
+	providers := []ProviderDescriptor{}
+	for _, p := range c.Providers() {
+		if !p.Removed {
+			providers = append(providers, p)
+		}
+	}
+	return providers
+}
+
+// cycleKeys return the cycles of c, key is the canonical representation of cycle by provider keys
+func (c *ContainerWrapper) cycleKeys(keys map[int]string) (map[string][]int, []string) {
+	result := make(map[string][]int)
+	order := []string{}
+	for _, cycle := range dependencyCycles(c.dependencyGraph(true), len(c.provideInfos)) {
+		ids := make([]int, 0, len(cycle))
+		for _, edge := range cycle {
+			ids = append(ids, edge.from)
+		}
+		// rotate to the smallest provider key, so that it is same between containers
+		start := 0
+		for i := range ids {
+			if keys[ids[i]] < keys[ids[start]] {
+				start = i
+			}
+		}
+		ids = append(ids[start:], ids[:start]...)
+		parts := make([]string, 0, len(ids))
+		for _, id := range ids {
+			parts = append(parts, keys[id])
+		}
+		key := strings.Join(parts, " -> ")
+		if _, ok := result[key]; !ok {
+			order = append(order, key)
+		}
+		result[key] = ids
+	}
+	return result, order
+}
+
+// diffInputs return the inputs in b but not in a, and the inputs in a but not in b,
+// the inputs are compared by all fields (include Optional)
+func diffInputs(a, b []ProvideInput) (added, removed []ProvideInput) {
+	count := make(map[ProvideInput]int)
+	for i := range a {
+		count[a[i]]++
+	}
+	for i := range b {
+		if count[b[i]] > 0 {
+			count[b[i]]--
+			continue
+		}
+		added = append(added, b[i])
+	}
+	for i := range a {
+		if count[a[i]] > 0 {
+			count[a[i]]--
+			removed = append(removed, a[i])
+		}
+	}
+	return added, removed
+}
+
+// DiffGraphs compare the dependency graphs (not include removed providers) of a and b,
+// and report added / removed providers, changed inputs of the providers with same outputs, new overrides and new cycles.
+// The providers are matched by outputs, so the change of location is ignored.
+// The result of GraphDiff.String() is stable, can be used in golden file test.
+//
+// for example
+//   a := digpro.New()
+//   _ = a.Supply(1) // please handle error in production
+//   _ = a.Provide(func(i int) string { return fmt.Sprint(i) })
+//   b := digpro.New()
+//   _ = b.Supply(true)
+//   _ = b.Provide(func(b bool) string { return fmt.Sprint(b) })
+//   fmt.Println(digpro.DiffGraphs(a, b).String())
+//   // Output:
+//   // added:
+//   // 	Supply bool at main.main (main.go:14)
+//   // removed:
+//   // 	Supply int at main.main (main.go:11)
+//   // changed:
+//   // 	Provide string at main.main.func2 (main.go:15)
+//   // 		+ bool
+//   // 		- int
+func DiffGraphs(a, b *ContainerWrapper) GraphDiff {
+	diff := GraphDiff{}
+	aProviders, bProviders := a.activeProviders(), b.activeProviders()
+	aKeys, bKeys := providerKeys(aProviders), providerKeys(bProviders)
+	aByKey := make(map[string]ProviderDescriptor, len(aProviders))
+	for _, p := range aProviders {
+		aByKey[aKeys[p.ID]] = p
+	}
+	bByKey := make(map[string]ProviderDescriptor, len(bProviders))
+	bByID := make(map[int]ProviderDescriptor, len(bProviders))
+	for _, p := range bProviders {
+		bByKey[bKeys[p.ID]] = p
+		bByID[p.ID] = p
+	}
+
+	for _, p := range bProviders {
+		old, ok := aByKey[bKeys[p.ID]]
+		if !ok {
+			diff.Added = append(diff.Added, p)
+		} else if added, removed := diffInputs(old.Inputs, p.Inputs); len(added) != 0 || len(removed) != 0 {
+			diff.Changed = append(diff.Changed, ProviderInputsChange{Provider: p, AddedInputs: added, RemovedInputs: removed})
+		}
+		if len(p.Overrides) != 0 && (!ok || len(old.Overrides) == 0) {
+			diff.NewOverrides = append(diff.NewOverrides, p)
+		}
+	}
+	for _, p := range aProviders {
+		if _, ok := bByKey[aKeys[p.ID]]; !ok {
+			diff.Removed = append(diff.Removed, p)
+		}
+	}
+
+	aCycles, _ := a.cycleKeys(aKeys)
+	bCycles, bCycleOrder := b.cycleKeys(bKeys)
+	for _, key := range bCycleOrder {
+		if _, ok := aCycles[key]; ok {
+			continue
+		}
+		cycle := make([]ProviderDescriptor, 0, len(bCycles[key]))
+		for _, id := range bCycles[key] {
+			cycle = append(cycle, bByID[id])
+		}
+		diff.NewCycles = append(diff.NewCycles, cycle)
+	}
+	return diff
+}
+
+// Empty return true if there is no difference
+func (d GraphDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0 && len(d.NewOverrides) == 0 && len(d.NewCycles) == 0
+}
+
+// describeProvider return the readable description of provider,
+// only the base name of file is used, so that the description is same between machines
+func describeProvider(p ProviderDescriptor) string {
+	outputs := make([]string, 0, len(p.Outputs))
+	for i := range p.Outputs {
+		outputs = append(outputs, p.Outputs[i].String())
+	}
+	s := fmt.Sprintf("%s %s", p.Kind, strings.Join(outputs, ", "))
+	if p.Location != nil {
+		s += fmt.Sprintf(" at %s.%s (%s:%d)", p.Location.Package, p.Location.Name, filepath.Base(p.Location.File), p.Location.Line)
+	}
+	return s
+}
+
+// describeInput return the readable description of input, mark the optional input
+func describeInput(in ProvideInput) string {
+	if in.Optional {
+		return in.String() + " (optional)"
+	}
+	return in.String()
+}
+
+// String return the readable diff, return empty string if there is no difference
+func (d GraphDiff) String() string {
+	b := strings.Builder{}
+	writeProviders := func(title string, providers []ProviderDescriptor) {
+		if len(providers) == 0 {
+			return
+		}
+		b.WriteString(title + ":\n")
+		for _, p := range providers {
+			b.WriteString("\t" + describeProvider(p) + "\n")
+		}
+	}
+	writeProviders("added", d.Added)
+	writeProviders("removed", d.Removed)
+	if len(d.Changed) != 0 {
+		b.WriteString("changed:\n")
+		for _, change := range d.Changed {
+			b.WriteString("\t" + describeProvider(change.Provider) + "\n")
+			for i := range change.AddedInputs {
+				b.WriteString("\t\t+ " + describeInput(change.AddedInputs[i]) + "\n")
+			}
+			for i := range change.RemovedInputs {
+				b.WriteString("\t\t- " + describeInput(change.RemovedInputs[i]) + "\n")
+			}
+		}
+	}
+	writeProviders("new overrides", d.NewOverrides)
+	if len(d.NewCycles) != 0 {
+		b.WriteString("new cycles:\n")
+		for _, cycle := range d.NewCycles {
+			b.WriteString("\t" + describeProvider(cycle[0]) + "\n")
+			for _, p := range cycle[1:] {
+				b.WriteString("\t\t-> " + describeProvider(p) + "\n")
+			}
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
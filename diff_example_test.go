@@ -0,0 +1,34 @@
+package digpro_test
+
+import (
+	"fmt"
+
+	"github.com/rectcircle/digpro"
+)
+
+type diffFoo struct{}
+
+func newDiffFooV1(s string, i int) *diffFoo { return &diffFoo{} }
+
+func newDiffFooV2(s string, b bool) *diffFoo { return &diffFoo{} }
+
+func ExampleDiffGraphs() {
+	a := digpro.New()
+	_ = a.Supply("a") // please handle error in production
+	_ = a.Supply(1)
+	_ = a.Provide(newDiffFooV1)
+	b := digpro.New()
+	_ = b.Supply("b")
+	_ = b.Supply(true)
+	_ = b.Provide(newDiffFooV2)
+	fmt.Println(digpro.DiffGraphs(a, b).String())
+	// Output:
+	// added:
+	// 	Supply bool at github.com/rectcircle/digpro_test.ExampleDiffGraphs (diff_example_test.go:22)
+	// removed:
+	// 	Supply int at github.com/rectcircle/digpro_test.ExampleDiffGraphs (diff_example_test.go:18)
+	// changed:
+	// 	Provide *digpro_test.diffFoo at github.com/rectcircle/digpro_test.newDiffFooV2 (diff_example_test.go:13)
+	// 		+ bool
+	// 		- int
+}
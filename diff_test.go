@@ -0,0 +1,167 @@
+package digpro
+
+import (
+	"regexp"
+	"testing"
+
+	"go.uber.org/dig"
+)
+
+func TestDiffGraphs(t *testing.T) {
+	// the function names and line numbers of locations are not stable in test
+	locationRegexp := regexp.MustCompile(`at \S+ \(diff_test\.go:\d+\)`)
+	tests := []struct {
+		name      string
+		prepareA  PrepareFunc
+		prepareB  PrepareFunc
+		wantEmpty bool
+		want      string
+	}{
+		{
+			name: "same",
+			prepareA: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Supply(1),
+					c.Provide(func(i int) string { return "a" }),
+				)
+			},
+			prepareB: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Provide(func(i int) string { return "b" }),
+					c.Supply(2),
+				)
+			},
+			wantEmpty: true,
+		},
+		{
+			name: "added removed and changed",
+			prepareA: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Supply(1),
+					c.Supply("a", dig.Group("g")),
+					c.Provide(func(i int) string { return "a" }),
+				)
+			},
+			prepareB: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Supply(true),
+					c.Supply("a", dig.Group("g")),
+					c.Supply("b", dig.Group("g")),
+					c.Provide(func(b bool) string { return "b" }),
+				)
+			},
+			want: `added:
+	Supply bool at diff_test.go
+	Supply string[group="g"] at diff_test.go
+removed:
+	Supply int at diff_test.go
+changed:
+	Provide string at diff_test.go
+		+ bool
+		- int`,
+		},
+		{
+			name: "optional changed",
+			prepareA: func(c *ContainerWrapper) error {
+				return c.Provide(func(in struct {
+					dig.In
+					I int `optional:"true"`
+				}) string {
+					return "a"
+				})
+			},
+			prepareB: func(c *ContainerWrapper) error {
+				return c.Provide(func(i int) string { return "b" })
+			},
+			want: `changed:
+	Provide string at diff_test.go
+		+ int
+		- int (optional)`,
+		},
+		{
+			name: "new override",
+			prepareA: func(c *ContainerWrapper) error {
+				return c.Supply(1)
+			},
+			prepareB: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Supply(1),
+					c.Supply(2, Override()),
+				)
+			},
+			want: `new overrides:
+	Supply int at diff_test.go`,
+		},
+		{
+			name: "new cycle",
+			prepareA: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Supply(1),
+					c.Supply("a"),
+					c.Struct(new(D1)),
+					c.Provide(func() *D2 { return &D2{} }),
+				)
+			},
+			prepareB: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Supply(1),
+					c.Supply("a"),
+					c.Struct(new(D1), ResolveCyclic()),
+					c.Struct(new(D2)),
+				)
+			},
+			want: `changed:
+	Struct *digpro.D2 at diff_test.go
+		+ *digpro.D1
+		+ string
+new cycles:
+	Struct *digpro.D1 at diff_test.go
+		-> Struct *digpro.D2 at diff_test.go`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := New(), New()
+			if err := tt.prepareA(a); err != nil {
+				t.Errorf("prepareA() error = %v", err)
+				return
+			}
+			if err := tt.prepareB(b); err != nil {
+				t.Errorf("prepareB() error = %v", err)
+				return
+			}
+			diff := DiffGraphs(a, b)
+			if diff.Empty() != tt.wantEmpty {
+				t.Errorf("DiffGraphs().Empty() = %v, want %v", diff.Empty(), tt.wantEmpty)
+			}
+			if got := locationRegexp.ReplaceAllString(diff.String(), "at diff_test.go"); got != tt.want {
+				t.Errorf("DiffGraphs().String() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffGraphs_largeAcyclic(t *testing.T) {
+	a := New(dig.DeferAcyclicVerification())
+	b := New(dig.DeferAcyclicVerification())
+	if err := firstError(provideLayeredGraph(a, 4, 30), provideLayeredGraph(b, 4, 30), b.Supply(1)); err != nil {
+		t.Errorf("prepare error = %v", err)
+		return
+	}
+	diff := DiffGraphs(a, b)
+	if len(diff.Added) != 1 || len(diff.Removed) != 0 || len(diff.Changed) != 0 || len(diff.NewCycles) != 0 {
+		t.Errorf("DiffGraphs() = %s, want only added Supply int", diff.String())
+	}
+}
+
+func BenchmarkDiffGraphs_largeAcyclic(b *testing.B) {
+	x := New(dig.DeferAcyclicVerification())
+	y := New(dig.DeferAcyclicVerification())
+	if err := firstError(provideLayeredGraph(x, 4, 30), provideLayeredGraph(y, 4, 30), y.Supply(1)); err != nil {
+		b.Fatalf("prepare error = %v", err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		DiffGraphs(x, y)
+	}
+}
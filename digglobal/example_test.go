@@ -112,3 +112,22 @@ func Example() {
 	// ### inspect node and value <see stderr> ###
 	// ### inspect dot graph <see stderr> ###
 }
+
+func ExampleSnapshot() {
+	s := digglobal.Snapshot()
+	digglobal.Supply(1.5)
+	f, _ := digglobal.Extract(float64(0))
+	digglobal.Restore(s)
+	_, err := digglobal.Extract(float64(0))
+	fmt.Println(f, err != nil)
+	// Output: 1.5 true
+}
+
+func ExampleReset() {
+	s := digglobal.Snapshot()
+	digglobal.Reset()
+	_, err := digglobal.Extract(Foo{})
+	digglobal.Restore(s)
+	fmt.Println(err != nil)
+	// Output: true
+}
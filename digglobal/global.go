@@ -1,14 +1,39 @@
 package digglobal
 
 import (
+	"context"
 	"io"
+	"runtime"
+	"strings"
+	"sync"
 
 	"github.com/rectcircle/digpro"
 	"github.com/rectcircle/digpro/internal"
 	"go.uber.org/dig"
 )
 
-var g = digpro.New()
+var (
+	g   = digpro.New()
+	gMu sync.RWMutex // protect g, which is replaced by Reset, Snapshot, Restore and WithTestContainer
+
+	testMu   sync.Mutex                  // serialize WithTestContainer of the tests not in WithTestContainer
+	ownersMu sync.Mutex                  // protect owners
+	owners   = map[string][]*testOwner{} // test name -> the running WithTestContainer calls of the test
+)
+
+func container() *digpro.ContainerWrapper {
+	gMu.RLock()
+	defer gMu.RUnlock()
+	return g
+}
+
+func swap(c *digpro.ContainerWrapper) *digpro.ContainerWrapper {
+	gMu.Lock()
+	defer gMu.Unlock()
+	old := g
+	g = c
+	return old
+}
 
 func panicIfError(err error) {
 	if err != nil {
@@ -20,44 +45,277 @@ func panicIfError(err error) {
 //
 // Note: if has error will panic
 func Provide(constructor interface{}, opts ...dig.ProvideOption) {
-	digpro.QuickPanic(g.Provide(constructor, opts...))
+	digpro.QuickPanic(container().Provide(constructor, opts...))
 }
 
 // Invoke see https://pkg.go.dev/go.uber.org/dig#Container.Invoke
 func Invoke(function interface{}, opts ...dig.InvokeOption) error {
-	return g.Invoke(function, append([]dig.InvokeOption{internal.LocationFixOption{CallSkip: 3}}, opts...)...)
+	return container().Invoke(function, append([]dig.InvokeOption{internal.LocationFixOption{CallSkip: 3}}, opts...)...)
 }
 
 // String see https://pkg.go.dev/go.uber.org/dig#Container.String
 func String() string {
-	return g.String()
+	return container().String()
 }
 
 // Supply see https://pkg.go.dev/github.com/rectcircle/digpro#ContainerWrapper.Supply
 //
 // Note: if has error will panic
 func Supply(value interface{}, opts ...dig.ProvideOption) {
-	panicIfError(g.Supply(value, append([]dig.ProvideOption{internal.LocationFixOption{CallSkip: 4}}, opts...)...))
+	panicIfError(container().Supply(value, append([]dig.ProvideOption{internal.LocationFixOption{CallSkip: 4}}, opts...)...))
+}
+
+// SupplyEnv see https://pkg.go.dev/github.com/rectcircle/digpro#ContainerWrapper.SupplyEnv
+//
+// Note: if has error will panic
+func SupplyEnv(key string, typ interface{}, opts ...dig.ProvideOption) {
+	panicIfError(container().SupplyEnv(key, typ, append([]dig.ProvideOption{internal.LocationFixOption{CallSkip: 4}}, opts...)...))
 }
 
 // Struct see https://pkg.go.dev/github.com/rectcircle/digpro#ContainerWrapper.Struct
 //
 // Note: if has error will panic
 func Struct(structOrStructPtr interface{}, opts ...dig.ProvideOption) {
-	panicIfError(g.Struct(structOrStructPtr, append([]dig.ProvideOption{internal.LocationFixOption{CallSkip: 4}}, opts...)...))
+	panicIfError(container().Struct(structOrStructPtr, append([]dig.ProvideOption{internal.LocationFixOption{CallSkip: 4}}, opts...)...))
+}
+
+// Config see https://pkg.go.dev/github.com/rectcircle/digpro#ContainerWrapper.Config
+//
+// Note: if has error will panic
+func Config(prefix string, document interface{}, structOrStructPtr interface{}, opts ...dig.ProvideOption) {
+	panicIfError(container().Config(prefix, document, structOrStructPtr, append([]dig.ProvideOption{internal.LocationFixOption{CallSkip: 4}}, opts...)...))
 }
 
 // Extract see https://pkg.go.dev/github.com/rectcircle/digpro#ContainerWrapper.Extract
 func Extract(typ interface{}, opts ...digpro.ExtractOption) (interface{}, error) {
-	return internal.ExtractWithLocationForPC(g.Invoke, 3, typ, opts...)
+	return container().Extract(typ, append(opts, internal.LocationFixOption{CallSkip: 3})...)
+}
+
+// ExtractOptional see https://pkg.go.dev/github.com/rectcircle/digpro#ContainerWrapper.ExtractOptional
+func ExtractOptional(typ interface{}, opts ...digpro.ExtractOption) (interface{}, bool, error) {
+	pc, _, _, _ := runtime.Caller(1)
+	return container().ExtractOptional(typ, append(opts, internal.LocationFixOption{PC: pc})...)
+}
+
+// ExtractInto see https://pkg.go.dev/github.com/rectcircle/digpro#ContainerWrapper.ExtractInto
+func ExtractInto(structPtr interface{}) error {
+	pc, _, _, _ := runtime.Caller(1)
+	return container().ExtractInto(structPtr, internal.LocationFixOption{PC: pc})
 }
 
 // Unwrap see https://pkg.go.dev/github.com/rectcircle/digpro#ContainerWrapper.Unwrap
 func Unwrap() *dig.Container {
-	return g.Unwrap()
+	return container().Unwrap()
 }
 
 // Visualize see https://pkg.go.dev/github.com/rectcircle/digpro#ContainerWrapper.Visualize
 func Visualize(w io.Writer, opts ...dig.VisualizeOption) error {
-	return g.Visualize(w, opts...)
+	return container().Visualize(w, opts...)
+}
+
+// ExportGraph see https://pkg.go.dev/github.com/rectcircle/digpro#ContainerWrapper.ExportGraph
+func ExportGraph(w io.Writer, format digpro.GraphFormat, opts ...digpro.GraphOption) error {
+	return container().ExportGraph(w, format, opts...)
+}
+
+// VisualizeMermaid see https://pkg.go.dev/github.com/rectcircle/digpro#ContainerWrapper.VisualizeMermaid
+func VisualizeMermaid(w io.Writer, opts ...digpro.GraphOption) error {
+	return container().VisualizeMermaid(w, opts...)
+}
+
+// VisualizePlantUML see https://pkg.go.dev/github.com/rectcircle/digpro#ContainerWrapper.VisualizePlantUML
+func VisualizePlantUML(w io.Writer, opts ...digpro.GraphOption) error {
+	return container().VisualizePlantUML(w, opts...)
+}
+
+// VisualizeFrom see https://pkg.go.dev/github.com/rectcircle/digpro#ContainerWrapper.VisualizeFrom
+func VisualizeFrom(w io.Writer, roots []interface{}, depth int, opts ...digpro.GraphOption) error {
+	return container().VisualizeFrom(w, roots, depth, opts...)
+}
+
+// Start see https://pkg.go.dev/github.com/rectcircle/digpro#ContainerWrapper.Start
+func Start(ctx context.Context) error {
+	return container().Start(ctx)
+}
+
+// Stop see https://pkg.go.dev/github.com/rectcircle/digpro#ContainerWrapper.Stop
+func Stop(ctx context.Context) error {
+	return container().Stop(ctx)
+}
+
+// Scope see https://pkg.go.dev/github.com/rectcircle/digpro#ContainerWrapper.Scope
+func Scope(name string) *digpro.ContainerWrapper {
+	return container().Scope(name)
+}
+
+// Apply see https://pkg.go.dev/github.com/rectcircle/digpro#ContainerWrapper.Apply
+//
+// Note: if has error will panic
+func Apply(modules ...digpro.ModuleOption) {
+	digpro.QuickPanic(container().Apply(modules...))
+}
+
+// RemoveGroupMembers see https://pkg.go.dev/github.com/rectcircle/digpro#ContainerWrapper.RemoveGroupMembers
+//
+// Note: if has error will panic
+func RemoveGroupMembers(typ interface{}, group string, locations ...string) {
+	digpro.QuickPanic(container().RemoveGroupMembers(typ, group, locations...))
+}
+
+// Remove see https://pkg.go.dev/github.com/rectcircle/digpro#ContainerWrapper.Remove
+//
+// Note: if has error will panic
+func Remove(typ interface{}, opts ...digpro.ExtractOption) {
+	digpro.QuickPanic(container().Remove(typ, opts...))
+}
+
+// Validate see https://pkg.go.dev/github.com/rectcircle/digpro#ContainerWrapper.Validate
+func Validate() error {
+	return container().Validate()
+}
+
+// Verify see https://pkg.go.dev/github.com/rectcircle/digpro#ContainerWrapper.Verify
+func Verify() error {
+	return container().Verify()
+}
+
+// UnusedProviders see https://pkg.go.dev/github.com/rectcircle/digpro#ContainerWrapper.UnusedProviders
+func UnusedProviders(roots ...interface{}) []string {
+	return container().UnusedProviders(roots...)
+}
+
+// Providers see https://pkg.go.dev/github.com/rectcircle/digpro#ContainerWrapper.Providers
+func Providers() []digpro.ProviderDescriptor {
+	return container().Providers()
+}
+
+// Container return the global container, for example, use it with package github.com/rectcircle/digpro/typed
+//   i, err := typed.Extract[int](digglobal.Container())
+//
+// Note: the global container may be replaced by Reset, Snapshot, Restore and WithTestContainer,
+// please call Container every time instead of holding the result
+func Container() *digpro.ContainerWrapper {
+	return container()
+}
+
+// Reset replace the global container with a fresh one (same as digpro.New()),
+// all providers registered before are dropped
+func Reset() {
+	swap(digpro.New())
+}
+
+// Snapshot return the current global container, and replace the global container with a clone of it
+// (see https://pkg.go.dev/github.com/rectcircle/digpro#ContainerWrapper.Clone),
+// so that the providers registered after Snapshot (include override) can be dropped by Restore.
+// The clone has the same providers but no constructed value, so the override changes the transitive dependents too,
+// and nothing is constructed in the returned container.
+//
+// Note: if has error will panic
+//
+// for example
+//   s := digglobal.Snapshot()
+//   defer digglobal.Restore(s)
+//   digglobal.Supply("b", digpro.Override())
+func Snapshot() *digpro.ContainerWrapper {
+	return clone()
+}
+
+// clone replace the global container with a clone of it, and return the origin one
+func clone() *digpro.ContainerWrapper {
+	gMu.Lock()
+	defer gMu.Unlock()
+	c, err := g.Clone()
+	panicIfError(err)
+	old := g
+	g = c
+	return old
+}
+
+// Restore replace the global container with c, c is usually returned by Snapshot
+func Restore(c *digpro.ContainerWrapper) {
+	swap(c)
+}
+
+// TestingT is implemented by *testing.T, *testing.B and testing.TB
+type TestingT interface {
+	Helper()
+	Name() string
+}
+
+// testOwner is a running WithTestContainer call
+type testOwner struct {
+	mu sync.Mutex // serialize WithTestContainer of the test and its subtests in f
+}
+
+// nearestOwner return the last running WithTestContainer call of the test named name or its parent tests, must hold ownersMu
+func nearestOwner(name string) *testOwner {
+	for {
+		if os := owners[name]; len(os) != 0 {
+			return os[len(os)-1]
+		}
+		i := strings.LastIndex(name, "/")
+		if i < 0 {
+			return nil
+		}
+		name = name[:i]
+	}
+}
+
+// acquireTestContainer wait until the test named name can replace the global container, and register it as an owner.
+// The tests not in WithTestContainer are serialized by testMu, and the calls in f of an owner are serialized by the owner
+func acquireTestContainer(name string) (release func()) {
+	ownersMu.Lock()
+	lock := &testMu
+	if parent := nearestOwner(name); parent != nil {
+		lock = &parent.mu
+	}
+	ownersMu.Unlock()
+	lock.Lock()
+
+	owner := &testOwner{}
+	ownersMu.Lock()
+	owners[name] = append(owners[name], owner)
+	ownersMu.Unlock()
+	return func() {
+		ownersMu.Lock()
+		// remove by identity instead of pop, the owners of a test may not return in order
+		os := owners[name]
+		for i := range os {
+			if os[i] == owner {
+				owners[name] = append(os[:i:i], os[i+1:]...)
+				break
+			}
+		}
+		if len(owners[name]) == 0 {
+			delete(owners, name)
+		}
+		ownersMu.Unlock()
+		lock.Unlock()
+	}
+}
+
+// WithTestContainer call f with a cloned global container (see Snapshot), and restore the global container after f returned,
+// so that the wiring of the test (e.g. digpro.Override()) is isolated from other tests.
+// Call Reset in f to start with a fresh container.
+//
+// t is usually *testing.T, see TestingT.
+//
+// The global container is shared by all goroutines, so the calls of WithTestContainer are serialized
+// (the calls in f and subtests of f are serialized by the call of their test), and every call restores the container replaced by itself.
+// That means the parallel tests (t.Parallel()) will not see the container of each other.
+// Please don't call t.Parallel() in f, don't call WithTestContainer with the same t in other goroutines,
+// and don't use the digglobal API in parallel tests without WithTestContainer.
+//
+// for example
+//   func TestFoo(t *testing.T) {
+//   	digglobal.WithTestContainer(t, func() {
+//   		digglobal.Supply("mock", digpro.Override())
+//   		// ...
+//   	})
+//   }
+func WithTestContainer(t TestingT, f func()) {
+	t.Helper()
+	defer acquireTestContainer(t.Name())()
+	defer Restore(clone())
+	f()
 }
@@ -0,0 +1,187 @@
+package digglobal_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/rectcircle/digpro"
+	"github.com/rectcircle/digpro/digglobal"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	origin := digglobal.Container()
+	tests := []struct {
+		name    string
+		mutate  func()
+		want    interface{}
+		wantErr bool
+	}{
+		{
+			name:   "supply",
+			mutate: func() { digglobal.Supply(1.5) },
+			want:   1.5,
+		},
+		{
+			name: "override",
+			mutate: func() {
+				digglobal.Supply(2.5)
+				digglobal.Supply(3.5, digpro.Override())
+			},
+			want: 3.5,
+		},
+		{
+			name:    "reset",
+			mutate:  digglobal.Reset,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := digglobal.Snapshot()
+			tt.mutate()
+			got, err := digglobal.Extract(float64(0))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("digglobal.Extract() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("digglobal.Extract() = %v, want %v", got, tt.want)
+			}
+			digglobal.Restore(s)
+			if digglobal.Container() != origin {
+				t.Errorf("digglobal.Container() is not restored")
+			}
+			if _, err := digglobal.Extract(float64(0)); err == nil {
+				t.Errorf("digglobal.Extract() error = nil after Restore, want error")
+			}
+		})
+	}
+}
+
+func TestWithTestContainer(t *testing.T) {
+	origin := digglobal.Container()
+	digglobal.WithTestContainer(t, func() {
+		if digglobal.Container() == origin {
+			t.Errorf("digglobal.Container() is not replaced")
+		}
+		digglobal.Supply(1.5)
+		t.Run("nested", func(t *testing.T) {
+			digglobal.WithTestContainer(t, func() {
+				digglobal.Supply(2.5, digpro.Override())
+				if got, err := digglobal.Extract(float64(0)); err != nil || got != 2.5 {
+					t.Errorf("digglobal.Extract() = %v, %v, want 2.5", got, err)
+				}
+			})
+			if got, err := digglobal.Extract(float64(0)); err != nil || got != 1.5 {
+				t.Errorf("digglobal.Extract() = %v, %v, want 1.5", got, err)
+			}
+		})
+		t.Run("fresh", func(t *testing.T) {
+			digglobal.WithTestContainer(t, func() {
+				digglobal.Reset()
+				if _, err := digglobal.Extract(float64(0)); err == nil {
+					t.Errorf("digglobal.Extract() error = nil after Reset, want error")
+				}
+			})
+		})
+	})
+	if digglobal.Container() != origin {
+		t.Errorf("digglobal.Container() is not restored")
+	}
+	if _, err := digglobal.Extract(float64(0)); err == nil {
+		t.Errorf("digglobal.Extract() error = nil after WithTestContainer, want error")
+	}
+}
+
+func TestWithTestContainer_parallel(t *testing.T) {
+	origin := digglobal.Container()
+	t.Run("group", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			want := float64(i)
+			t.Run(fmt.Sprint(i), func(t *testing.T) {
+				t.Parallel()
+				digglobal.WithTestContainer(t, func() {
+					digglobal.Supply(want)
+					outer := digglobal.Container()
+					t.Run("nested", func(t *testing.T) {
+						digglobal.WithTestContainer(t, func() {
+							digglobal.Supply(want+0.5, digpro.Override())
+							inner := digglobal.Container()
+							// call with the same t again
+							digglobal.WithTestContainer(t, func() {
+								digglobal.Reset()
+							})
+							if digglobal.Container() != inner {
+								t.Errorf("digglobal.Container() is not restored to the container of the nested call")
+							}
+						})
+					})
+					if digglobal.Container() != outer {
+						t.Errorf("digglobal.Container() is not restored to the container of the test")
+					}
+					if got, err := digglobal.Extract(float64(0)); err != nil || got != want {
+						t.Errorf("digglobal.Extract() = %v, %v, want %v", got, err, want)
+					}
+				})
+			})
+		}
+	})
+	if digglobal.Container() != origin {
+		t.Errorf("digglobal.Container() is not restored after parallel tests")
+	}
+}
+
+type testDBI interface{ Name() string }
+
+type testDB struct{ name string }
+
+func (db testDB) Name() string { return db.name }
+
+type testSvc struct {
+	DB testDBI
+}
+
+func TestWithTestContainer_overrideTransitive(t *testing.T) {
+	digglobal.WithTestContainer(t, func() {
+		realCalled := 0
+		digglobal.Provide(func() testDBI { realCalled++; return testDB{name: "real"} })
+		digglobal.Struct(new(testSvc))
+		outer := digglobal.Container()
+		t.Run("mock", func(t *testing.T) {
+			digglobal.WithTestContainer(t, func() {
+				digglobal.Provide(func() testDBI { return testDB{name: "mock"} }, digpro.Override())
+				svc, err := digglobal.Extract(new(testSvc))
+				if err != nil || svc.(*testSvc).DB.Name() != "mock" {
+					t.Errorf("digglobal.Extract() = %v, %v, want mock DB", svc, err)
+				}
+			})
+		})
+		if realCalled != 0 {
+			t.Errorf("real constructor called %d times in WithTestContainer, want 0", realCalled)
+		}
+		if digglobal.Container() != outer {
+			t.Errorf("digglobal.Container() is not restored")
+		}
+		svc, err := digglobal.Extract(new(testSvc))
+		if err != nil || svc.(*testSvc).DB.Name() != "real" {
+			t.Errorf("digglobal.Extract() = %v, %v, want real DB", svc, err)
+		}
+	})
+}
+
+func TestExtractInto_location(t *testing.T) {
+	digglobal.WithTestContainer(t, func() {
+		digglobal.Reset()
+		var target struct {
+			A int
+		}
+		err := digglobal.ExtractInto(&target)
+		if err == nil {
+			t.Errorf("digglobal.ExtractInto() error = nil, want error")
+			return
+		}
+		if !strings.Contains(err.Error(), "TestExtractInto_location") || strings.Contains(err.Error(), "digglobal/global.go") {
+			t.Errorf("digglobal.ExtractInto() error = %v, want location of caller", err)
+		}
+	})
+}
@@ -17,6 +17,21 @@ type ContainerWrapper struct {
 	provideInfos             []internal.ProvideInfosWrapper
 	existResolveCyclicOption bool
 	propertyInjects          map[internal.ProvideOutput]*internal.PropertyInfo
+	lifecycle                *lifecycle
+	digOptions               []dig.Option
+	registrations            []registration // for Clone
+	// for scope, see Scope
+	parent         *ContainerWrapper
+	name           string
+	scopePC        uintptr
+	parentSynced   int // parent.provideInfos[:parentSynced] has been synced
+	syncingParent  bool
+	bridgedOutputs map[internal.ProvideOutput]struct{}
+	// the outputs of digpro.Lazy[T] provided, see Lazy
+	lazyProvided map[internal.ProvideOutput]struct{}
+	// for value group, see ExtractGroupOrdered
+	groupMemberOptions map[int]groupMemberOptions // key is index of provideInfos
+	groupValuesRecords map[internal.ProvideOutput][]groupValuesRecord
 }
 
 // New constructs a dig.Container wrapper and export some metholds.
@@ -32,14 +47,26 @@ type ContainerWrapper struct {
 //   c.Struct(...)
 //
 func New(opts ...dig.Option) *ContainerWrapper {
-	return &ContainerWrapper{
+	c := &ContainerWrapper{
 		Container: *dig.New(opts...),
 		middlewares: []provideMiddleware{
+			envDefaultProvideMiddleware,
+			lazyProvideMiddleware,
+			lifecycleProvideMiddleware,
+			groupProvideMiddleware,
 			resolveCyclicProvideMiddleware,
 			overrideProvideMiddleware,
 		},
-		propertyInjects: make(map[internal.ProvideOutput]*internal.PropertyInfo),
+		propertyInjects:    make(map[internal.ProvideOutput]*internal.PropertyInfo),
+		lifecycle:          newLifecycle(),
+		digOptions:         opts,
+		groupMemberOptions: make(map[int]groupMemberOptions),
+		groupValuesRecords: make(map[internal.ProvideOutput][]groupValuesRecord),
+		lazyProvided:       make(map[internal.ProvideOutput]struct{}),
 	}
+	// provide Lifecycle by dig.Container directly, it is not a user provider
+	_ = c.Container.Provide(func() Lifecycle { return c.lifecycle })
+	return c
 }
 
 // Unwrap *ContainerWrapper to obtain *dig.Container.
@@ -59,17 +86,21 @@ func (c *ContainerWrapper) Visualize(w io.Writer, opts ...dig.VisualizeOption) e
 //
 // digpro.ContainerWrapper.Provide() support digpro.Override() options, but dig.Container.Provide() not support
 func (c *ContainerWrapper) Provide(constructor interface{}, opts ...dig.ProvideOption) error {
-	return newProvideContext(c, constructor, opts).next()
+	c.syncParent()
+	err := newProvideContext(c, constructor, opts).next()
+	if err != nil {
+		return err
+	}
+	c.recordProvide(constructor, opts)
+	return nil
 }
 
 func (c *ContainerWrapper) Invoke(function interface{}, opts ...dig.InvokeOption) error {
-
+	c.syncParent()
+	c.provideLazyInputs(invokeInputs(function))
 	_opts, digproOpts := filterInvokeOptionAndGetDigproOptions(opts, locationFixOptionType)
 	opts = _opts
-	callSkip := digproOpts.locationFixCallSkip
-	if callSkip == 0 {
-		callSkip = 2
-	}
+	locationFix := digproOpts.locationFix(2)
 
 	// pruning
 	if !c.existResolveCyclicOption {
@@ -94,7 +125,7 @@ func (c *ContainerWrapper) Invoke(function interface{}, opts ...dig.InvokeOption
 	for i := 0; i < ftype.NumIn(); i++ {
 		inTypes = append(inTypes, ftype.In(i))
 	}
-	err := internal.WrapErrorWithLocationForPC(callSkip, func(pc uintptr) error {
+	err := internal.WrapErrorWithLocationFix(locationFix, func(pc uintptr) error {
 		return c.Container.Invoke(reflect.MakeFunc(reflect.FuncOf(inTypes, nil, false), func(args []reflect.Value) (results []reflect.Value) {
 			// for everyone arg call doPropertyInject
 			for _, arg := range args {
@@ -137,15 +168,24 @@ type provideContext struct {
 	constructor interface{}
 	opts        []dig.ProvideOption
 	index       int
+	kind        internal.ProviderKind // set by internal.ProviderKindOption
 }
 
 func newProvideContext(c *ContainerWrapper, constructor interface{}, opts []dig.ProvideOption) *provideContext {
-	return &provideContext{
+	pc := &provideContext{
 		c:           c,
 		constructor: constructor,
-		opts:        opts,
+		opts:        make([]dig.ProvideOption, 0, len(opts)),
 		index:       -1,
 	}
+	for _, opt := range opts {
+		if o, ok := opt.(internal.ProviderKindOption); ok {
+			pc.kind = o.Kind
+		} else {
+			pc.opts = append(pc.opts, opt)
+		}
+	}
+	return pc
 }
 
 func (pc *provideContext) next() error {
@@ -177,8 +217,19 @@ func (pc *provideContext) doProvide() error {
 	if internalOpts.Info != nil {
 		info.ProvideInfo = *internalOpts.Info
 	}
+	// the node of constructor has been appended to dig.Container.nodes
+	nodesValue := internal.EnsureValueExported(reflect.ValueOf(&pc.c.Container).Elem().FieldByName("nodes"))
+	info.Node = nodesValue.Index(nodesValue.Len() - 1)
+	info.Kind = pc.kind
 	pc.c.provideInfos = append(pc.c.provideInfos, info)
 	return nil
 }
 
+// setLastProviderKind set the kind of the provider registered just now
+func (c *ContainerWrapper) setLastProviderKind(kind internal.ProviderKind) {
+	if len(c.provideInfos) != 0 {
+		c.provideInfos[len(c.provideInfos)-1].Kind = kind
+	}
+}
+
 type provideMiddleware func(pc *provideContext) error
@@ -0,0 +1,150 @@
+package digpro
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+
+	"github.com/rectcircle/digpro/internal"
+	"go.uber.org/dig"
+)
+
+type envDefaultProvideOption struct {
+	dig.ProvideOption
+	value string
+}
+
+// EnvDefault set the default value for *digpro.ContainerWrapper.SupplyEnv, when the environment variable is not present.
+// The default value will be parsed as same as the environment variable.
+// Only support SupplyEnv, other methods (e.g. Provide, Supply and Struct) will return error.
+func EnvDefault(value string) dig.ProvideOption {
+	return envDefaultProvideOption{value: value}
+}
+
+var envDefaultProvideOptionType = reflect.TypeOf(envDefaultProvideOption{})
+
+// envDefaultProvideMiddleware reject digpro.EnvDefault(), which is consumed by SupplyEnv, and not a dig.ProvideOption
+func envDefaultProvideMiddleware(pc *provideContext) error {
+	for _, opt := range pc.opts {
+		if _, ok := opt.(envDefaultProvideOption); ok {
+			return errors.New("digpro.EnvDefault() only support SupplyEnv")
+		}
+	}
+	return pc.next()
+}
+
+func envTypeOf(typ interface{}) (reflect.Type, error) {
+	if typ == nil {
+		return nil, fmt.Errorf("typ want bool, int*, uint*, float*, string, time.Duration or slice of these, but got nil")
+	}
+	t := reflect.TypeOf(typ)
+	if !internal.IsParseValueSupported(t) {
+		return nil, fmt.Errorf("typ want bool, int*, uint*, float*, string, time.Duration or slice of these, but got %s", t)
+	}
+	return t, nil
+}
+
+func parseEnv(key string, t reflect.Type, value string) (reflect.Value, error) {
+	v, err := internal.ParseValue(t, value)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("parse environment variable %s=%q to %s error: %w", key, value, t, err)
+	}
+	return v, nil
+}
+
+// Env make a constructor, which read the environment variable key and parse it to the type of typ when it is called.
+// If the environment variable is not present, the constructor will return error.
+//
+// typ support bool, int*, uint*, float*, string, time.Duration and slice of these (comma-separated)
+//
+// for example
+//   os.Setenv("PORT", "8080")
+//   c := dig.New()
+//   _ = c.Provide(digpro.Env("PORT", int(0)), dig.Name("PORT")) // please handle error in production
+//   port, _ := digpro.Extract(c, int(0), digpro.ExtractByName("PORT"))
+//   fmt.Println(port)
+//   // Output: 8080
+func Env(key string, typ interface{}) interface{} {
+	t, err := envTypeOf(typ)
+	if err != nil {
+		return wrapError("Env", err)
+	}
+	ft := reflect.FuncOf([]reflect.Type{}, []reflect.Type{t, internal.ErrorType}, false)
+	fv := reflect.MakeFunc(ft, func([]reflect.Value) []reflect.Value {
+		var err error
+		value := reflect.New(t).Elem()
+		if s, ok := os.LookupEnv(key); !ok {
+			err = fmt.Errorf("environment variable %s is not present", key)
+		} else if v, parseErr := parseEnv(key, t, s); parseErr != nil {
+			err = parseErr
+		} else {
+			value = v
+		}
+		errValue := reflect.New(internal.ErrorType).Elem()
+		if err != nil {
+			errValue = reflect.ValueOf(wrapError("Env", err))
+		}
+		return []reflect.Value{value, errValue}
+	})
+	return fv.Interface()
+}
+
+// SupplyEnv read the environment variable key and parse it to the type of typ, and supply it into container.
+// The value is named key by default, use dig.Name or dig.Group option to change it.
+//
+// typ support bool, int*, uint*, float*, string, time.Duration and slice of these (comma-separated)
+//
+// If the environment variable is not present and digpro.EnvDefault option is not set,
+// nothing will be supplied, so that the required dependency will get the missing dependencies error of dig,
+// and the optional dependency will get zero value.
+//
+// for example
+//   os.Setenv("PORT", "8080")
+//   c := digpro.New()
+//   digpro.QuickPanic(
+//   	c.SupplyEnv("PORT", int(0)),
+//   	c.SupplyEnv("HOSTS", []string{}, digpro.EnvDefault("a.com,b.com")),
+//   )
+//   port, _ := c.Extract(int(0), digpro.ExtractByName("PORT"))
+//   hosts, _ := c.Extract([]string{}, digpro.ExtractByName("HOSTS"))
+//   fmt.Println(port, hosts)
+//   // Output: 8080 [a.com b.com]
+func (c *ContainerWrapper) SupplyEnv(key string, typ interface{}, opts ...dig.ProvideOption) error {
+	filteredOpts, digproOptsResult := filterProvideOptionAndGetDigproOptions(opts, locationFixOptionType)
+	locationFix := digproOptsResult.locationFix(3)
+	var defaultValue *string
+	opts = make([]dig.ProvideOption, 0, len(filteredOpts))
+	for _, opt := range filteredOpts {
+		if o, ok := opt.(envDefaultProvideOption); ok {
+			defaultValue = &o.value
+		} else {
+			opts = append(opts, opt)
+		}
+	}
+
+	t, err := envTypeOf(typ)
+	if err != nil {
+		return wrapError("SupplyEnv", err)
+	}
+	s, ok := os.LookupEnv(key)
+	if !ok && defaultValue == nil {
+		return nil
+	}
+	if !ok {
+		s = *defaultValue
+	}
+	value, err := parseEnv(key, t, s)
+	if err != nil {
+		return wrapError("SupplyEnv", err)
+	}
+	if provideOptions := internal.ApplyProvideOptions(opts...); provideOptions.Name == "" && provideOptions.Group == "" {
+		opts = append([]dig.ProvideOption{dig.Name(key)}, opts...)
+	}
+	err = internal.ProvideWithLocationFix(c.Provide, locationFix, Supply(value.Interface()), opts...)
+	if err != nil {
+		return err
+	}
+	c.setLastProviderKind(internal.ProviderKindSupply)
+	return nil
+}
@@ -0,0 +1,35 @@
+package digpro_test
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rectcircle/digpro"
+	"go.uber.org/dig"
+)
+
+func ExampleEnv() {
+	os.Setenv("PORT", "8080")
+	defer os.Unsetenv("PORT")
+	c := dig.New()
+	_ = c.Provide(digpro.Env("PORT", int(0)), dig.Name("PORT")) // please handle error in production
+	port, _ := digpro.Extract(c, int(0), digpro.ExtractByName("PORT"))
+	fmt.Println(port)
+	// Output: 8080
+}
+
+func ExampleContainerWrapper_SupplyEnv() {
+	os.Setenv("PORT", "8080")
+	defer os.Unsetenv("PORT")
+	c := digpro.New()
+	digpro.QuickPanic(
+		c.SupplyEnv("PORT", int(0)),
+		c.SupplyEnv("HOSTS", []string{}, digpro.EnvDefault("a.com,b.com")),
+		c.SupplyEnv("NOT_PRESENT", ""), // not present, nothing will be supplied
+	)
+	port, _ := c.Extract(int(0), digpro.ExtractByName("PORT"))
+	hosts, _ := c.Extract([]string{}, digpro.ExtractByName("HOSTS"))
+	_, err := c.Extract("", digpro.ExtractByName("NOT_PRESENT"))
+	fmt.Println(port, hosts, err != nil)
+	// Output: 8080 [a.com b.com] true
+}
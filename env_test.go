@@ -0,0 +1,263 @@
+package digpro
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/dig"
+)
+
+func TestEnv(t *testing.T) {
+	os.Setenv("DIGPRO_TEST_ENV_INT", "1")
+	os.Setenv("DIGPRO_TEST_ENV_INVALID_INT", "a")
+	defer os.Unsetenv("DIGPRO_TEST_ENV_INT")
+	defer os.Unsetenv("DIGPRO_TEST_ENV_INVALID_INT")
+	type args struct {
+		key string
+		typ interface{}
+	}
+	tests := []struct {
+		name                  string
+		args                  args
+		wantErr               bool
+		want                  interface{}
+		wantExtractErr        bool
+		wantExtractErrContain string
+	}{
+		{
+			name: "error type",
+			args: args{
+				key: "DIGPRO_TEST_ENV_INT",
+				typ: struct{}{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "error not present",
+			args: args{
+				key: "DIGPRO_TEST_ENV_NOT_PRESENT",
+				typ: int(0),
+			},
+			want:                  int(0),
+			wantExtractErr:        true,
+			wantExtractErrContain: "[Env] environment variable DIGPRO_TEST_ENV_NOT_PRESENT is not present",
+		},
+		{
+			name: "error parse",
+			args: args{
+				key: "DIGPRO_TEST_ENV_INVALID_INT",
+				typ: int(0),
+			},
+			want:                  int(0),
+			wantExtractErr:        true,
+			wantExtractErrContain: `parse environment variable DIGPRO_TEST_ENV_INVALID_INT="a" to int error`,
+		},
+		{
+			name: "success",
+			args: args{
+				key: "DIGPRO_TEST_ENV_INT",
+				typ: int(0),
+			},
+			want: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := dig.New()
+			err := c.Provide(Env(tt.args.key, tt.args.typ))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("c.Provide(Env()) error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			got, err := Extract(c, tt.want)
+			if (err != nil) != tt.wantExtractErr {
+				t.Errorf("Extract() error = %v, wantErr %v", err, tt.wantExtractErr)
+				return
+			}
+			if err != nil {
+				if !strings.Contains(err.Error(), tt.wantExtractErrContain) {
+					t.Errorf("Extract() error = %v, want contain = %s", err, tt.wantExtractErrContain)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Extract() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContainerWrapper_SupplyEnv(t *testing.T) {
+	os.Setenv("DIGPRO_TEST_ENV_DURATION", "1s")
+	os.Setenv("DIGPRO_TEST_ENV_SLICE", "a,b")
+	os.Setenv("DIGPRO_TEST_ENV_INVALID_INT", "a")
+	defer os.Unsetenv("DIGPRO_TEST_ENV_DURATION")
+	defer os.Unsetenv("DIGPRO_TEST_ENV_SLICE")
+	defer os.Unsetenv("DIGPRO_TEST_ENV_INVALID_INT")
+	type args struct {
+		key  string
+		typ  interface{}
+		opts []dig.ProvideOption
+	}
+	tests := []struct {
+		name           string
+		args           args
+		wantErr        bool
+		wantErrContain string
+		want           interface{}
+		wantOpts       []ExtractOption
+		wantExtractErr bool
+	}{
+		{
+			name: "error type",
+			args: args{
+				key: "DIGPRO_TEST_ENV_DURATION",
+				typ: nil,
+			},
+			wantErr:        true,
+			wantErrContain: "[SupplyEnv]",
+		},
+		{
+			name: "error parse",
+			args: args{
+				key: "DIGPRO_TEST_ENV_INVALID_INT",
+				typ: int(0),
+			},
+			wantErr:        true,
+			wantErrContain: `parse environment variable DIGPRO_TEST_ENV_INVALID_INT="a" to int error`,
+		},
+		{
+			name: "error parse default",
+			args: args{
+				key:  "DIGPRO_TEST_ENV_NOT_PRESENT",
+				typ:  int(0),
+				opts: []dig.ProvideOption{EnvDefault("a")},
+			},
+			wantErr:        true,
+			wantErrContain: `parse environment variable DIGPRO_TEST_ENV_NOT_PRESENT="a" to int error`,
+		},
+		{
+			name: "not present missing dependencies",
+			args: args{
+				key: "DIGPRO_TEST_ENV_NOT_PRESENT",
+				typ: int(0),
+			},
+			want:           int(0),
+			wantOpts:       []ExtractOption{ExtractByName("DIGPRO_TEST_ENV_NOT_PRESENT")},
+			wantExtractErr: true,
+		},
+		{
+			name: "not present default",
+			args: args{
+				key:  "DIGPRO_TEST_ENV_NOT_PRESENT",
+				typ:  int(0),
+				opts: []dig.ProvideOption{EnvDefault("1")},
+			},
+			want:     1,
+			wantOpts: []ExtractOption{ExtractByName("DIGPRO_TEST_ENV_NOT_PRESENT")},
+		},
+		{
+			name: "duration",
+			args: args{
+				key:  "DIGPRO_TEST_ENV_DURATION",
+				typ:  time.Duration(0),
+				opts: []dig.ProvideOption{EnvDefault("2s")},
+			},
+			want:     time.Second,
+			wantOpts: []ExtractOption{ExtractByName("DIGPRO_TEST_ENV_DURATION")},
+		},
+		{
+			name: "slice with name",
+			args: args{
+				key:  "DIGPRO_TEST_ENV_SLICE",
+				typ:  []string{},
+				opts: []dig.ProvideOption{dig.Name("slice")},
+			},
+			want:     []string{"a", "b"},
+			wantOpts: []ExtractOption{ExtractByName("slice")},
+		},
+		{
+			name: "slice with group",
+			args: args{
+				key:  "DIGPRO_TEST_ENV_SLICE",
+				typ:  []string{},
+				opts: []dig.ProvideOption{dig.Group("slice")},
+			},
+			want:     [][]string{{"a", "b"}},
+			wantOpts: []ExtractOption{ExtractByGroup("slice")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+			err := c.SupplyEnv(tt.args.key, tt.args.typ, tt.args.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("c.SupplyEnv() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				if !strings.Contains(err.Error(), tt.wantErrContain) {
+					t.Errorf("c.SupplyEnv() error = %v, want contain = %s", err, tt.wantErrContain)
+				}
+				return
+			}
+			got, err := c.Extract(tt.want, tt.wantOpts...)
+			if (err != nil) != tt.wantExtractErr {
+				t.Errorf("c.Extract() error = %v, wantErr %v", err, tt.wantExtractErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("c.Extract() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnvDefault_notSupplyEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare PrepareFunc
+	}{
+		{
+			name: "provide",
+			prepare: func(c *ContainerWrapper) error {
+				return c.Provide(func() int { return 1 }, EnvDefault("1"))
+			},
+		},
+		{
+			name: "supply",
+			prepare: func(c *ContainerWrapper) error {
+				return c.Supply(1, EnvDefault("1"))
+			},
+		},
+		{
+			name: "struct",
+			prepare: func(c *ContainerWrapper) error {
+				return c.Struct(new(verifyFoo), EnvDefault("1"))
+			},
+		},
+		{
+			name: "struct resolve cyclic",
+			prepare: func(c *ContainerWrapper) error {
+				return c.Struct(new(verifyFoo), EnvDefault("1"), ResolveCyclic())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.prepare(New())
+			if err == nil || !strings.Contains(err.Error(), "digpro.EnvDefault() only support SupplyEnv") {
+				t.Errorf("prepare() error = %v, want digpro.EnvDefault() only support SupplyEnv", err)
+			}
+		})
+	}
+}
@@ -1,6 +1,11 @@
 package digpro
 
 import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+
 	"github.com/rectcircle/digpro/internal"
 	"go.uber.org/dig"
 )
@@ -60,6 +65,9 @@ func MakeExtractFunc(ptr interface{}, opts ...ExtractOption) interface{} {
 //   fmt.Println(i.(int) == 1)
 //   // Output: true
 func Extract(c *dig.Container, typ interface{}, opts ...ExtractOption) (interface{}, error) {
+	if internal.ApplyExtractOptions(opts...).GroupOrdered {
+		return nil, wrapError("Extract", errors.New("digpro.ExtractGroupOrdered() only support *digpro.ContainerWrapper"))
+	}
 	return internal.ExtractWithLocationForPC(c.Invoke, 3, typ, opts...)
 }
 
@@ -82,5 +90,192 @@ func Extract(c *dig.Container, typ interface{}, opts ...ExtractOption) (interfac
 //   fmt.Println(i.(int) == 1)
 //   // Output: true
 func (c *ContainerWrapper) Extract(typ interface{}, opts ...ExtractOption) (interface{}, error) {
-	return internal.ExtractWithLocationForPC(c.Invoke, 3, typ, opts...)
+	return c.extract(2, typ, opts...)
+}
+
+// ExtractOptional like Extract, but return zero value of the type and false, if the type is not provided.
+// Note: if the type is provided, but the constructor or its dependencies failed, the error will be returned.
+//
+// for example
+//   c := dig.New()
+//   _ = c.Provide(func() int { return 1 }) // please handle error in production
+//   i, found, _ := digpro.ExtractOptional(c, int(0))
+//   s, found2, _ := digpro.ExtractOptional(c, "")
+//   fmt.Printf("%v %v %q %v\n", i, found, s, found2)
+//   // Output: 1 true "" false
+func ExtractOptional(c *dig.Container, typ interface{}, opts ...ExtractOption) (interface{}, bool, error) {
+	return internal.ExtractOptionalWithLocationForPC(c.Invoke, 3, typ, opts...)
+}
+
+// ExtractOptional like Extract, but return zero value of the type and false, if the type is not provided.
+// Note: if the type is provided, but the constructor or its dependencies failed, the error will be returned.
+// Like Extract, support digpro.ExtractGroupOrdered() and extract value group into map[string]T.
+//
+// for example
+//   c := digpro.New()
+//   _ = c.Supply(1) // please handle error in production
+//   i, found, _ := c.ExtractOptional(int(0))
+//   s, found2, _ := c.ExtractOptional("")
+//   fmt.Printf("%v %v %q %v\n", i, found, s, found2)
+//   // Output: 1 true "" false
+func (c *ContainerWrapper) ExtractOptional(typ interface{}, opts ...ExtractOption) (interface{}, bool, error) {
+	if typ != nil && internal.ApplyExtractOptions(opts...).Group != "" {
+		// value group is never missing, extract support digpro.ExtractGroupOrdered() and map[string]T
+		value, err := c.extract(2, typ, opts...)
+		return value, err == nil, err
+	}
+	return internal.ExtractOptionalWithLocationForPC(c.Invoke, 3, typ, opts...)
+}
+
+// makeExtractIntoFunc make Invoke function to fill all fields (except the index in except) of *structPtr, use same tags as Struct
+func makeExtractIntoFunc(structPtr interface{}, c *dig.Container, except map[int]struct{}) (interface{}, error) {
+	if structPtr == nil || reflect.TypeOf(structPtr).Kind() != reflect.Ptr {
+		return nil, fmt.Errorf("structPtr want non nil struct pointer, but got %#v", structPtr)
+	}
+	parameterObjectType, fieldMapping, err := makeParameterObjectType(structPtr, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(except) != 0 {
+		fields := []reflect.StructField{}
+		for i := 0; i < parameterObjectType.NumField(); i++ {
+			f := parameterObjectType.Field(i)
+			if index, ok := fieldMapping[f.Name]; ok {
+				if _, ok := except[index]; ok {
+					delete(fieldMapping, f.Name)
+					continue
+				}
+			}
+			fields = append(fields, f)
+		}
+		parameterObjectType = reflect.StructOf(fields)
+	}
+	defaults, err := makeStructDefaults(structPtr)
+	if err != nil {
+		return nil, err
+	}
+	ft := reflect.FuncOf([]reflect.Type{parameterObjectType}, []reflect.Type{}, false)
+	fv := reflect.MakeFunc(ft, func(p []reflect.Value) []reflect.Value {
+		// structPtr is a pointer, so fields will be set in place, and error only occur when structPtr is invalid
+		_, _ = copyFromParameterObject(structPtr, p[0], fieldMapping, defaults, func(output internal.ProvideOutput) bool {
+			return isProvided(c, output)
+		})
+		return nil
+	})
+	return fv.Interface(), nil
+}
+
+// ExtractInto fill all fields of the struct pointed by structPtr from dig.Container in a single Invoke.
+//
+// support same tags as digpro.Struct (name, group, optional, default and `digpro:"ignore"`),
+// if extract failed, *structPtr will not be changed.
+//
+// for example
+//   type Deps struct {
+//   	A string
+//   	B int `name:"b"`
+//   	C bool `optional:"true"`
+//   }
+//   c := dig.New()
+//   _ = c.Provide(func() string { return "a" }) // please handle error in production
+//   _ = c.Provide(func() int { return 1 }, dig.Name("b"))
+//   var deps Deps
+//   _ = digpro.ExtractInto(c, &deps)
+//   fmt.Printf("%#v\n", deps)
+//   // Output: digpro_test.Deps{A:"a", B:1, C:false}
+func ExtractInto(c *dig.Container, structPtr interface{}) error {
+	f, err := makeExtractIntoFunc(structPtr, c, nil)
+	if err != nil {
+		return wrapError("ExtractInto", err)
+	}
+	return internal.WrapErrorWithLocationForPC(2, func(uintptr) error { return c.Invoke(f) })
+}
+
+// ExtractInto fill all fields of the struct pointed by structPtr from container in a single Invoke.
+//
+// support same tags as *digpro.ContainerWrapper.Struct (name, group, optional, default and `digpro:"ignore"`),
+// the field of map[string]T with group tag will be filled like Extract (see digpro.GroupMemberName).
+// if extract failed, *structPtr will not be changed.
+//
+// for example
+//   type Deps struct {
+//   	A string
+//   	B int `name:"b"`
+//   	C bool `optional:"true"`
+//   }
+//   c := digpro.New()
+//   _ = c.Supply("a") // please handle error in production
+//   _ = c.Supply(1, dig.Name("b"))
+//   var deps Deps
+//   _ = c.ExtractInto(&deps)
+//   fmt.Printf("%#v\n", deps)
+//   // Output: digpro_test.Deps{A:"a", B:1, C:false}
+//
+// opts is reserved for the wrappers (e.g. digglobal.ExtractInto) to fix the error location,
+// the name and group of fields should be set by tags, so digpro.ExtractByName(), digpro.ExtractByGroup()
+// and digpro.ExtractGroupOrdered() are not supported.
+func (c *ContainerWrapper) ExtractInto(structPtr interface{}, opts ...ExtractOption) error {
+	options := internal.ApplyExtractOptions(opts...)
+	if options.Name != "" || options.Group != "" || options.GroupOrdered {
+		return wrapError("ExtractInto", errors.New("name and group options are not supported, please use struct tags"))
+	}
+	pc, _, _, _ := runtime.Caller(1)
+	if options.LocationFix != nil {
+		if options.LocationFix.PC != 0 {
+			pc = options.LocationFix.PC
+		} else if options.LocationFix.CallSkip > 0 {
+			// CallSkip is counted from ExtractInto, 1 is the caller of ExtractInto
+			pc, _, _, _ = runtime.Caller(options.LocationFix.CallSkip)
+		}
+	}
+	// the value group fields of map[string]T are extracted by Extract, see digpro.GroupMemberName
+	mapGroupValues := map[int]reflect.Value{}
+	if _, structTyp, err := structTypeOf(structPtr); err == nil {
+		for i := 0; i < structTyp.NumField(); i++ {
+			f := structTyp.Field(i)
+			group := f.Tag.Get(internal.DigGroupTag)
+			if group == "" || f.Type.Kind() != reflect.Map || f.Tag.Get("digpro") == "ignore" {
+				continue
+			}
+			value, err := c.extract(0, reflect.Zero(f.Type).Interface(), ExtractByGroup(group), internal.LocationFixOption{PC: pc})
+			if err != nil {
+				return err
+			}
+			mapGroupValues[i] = reflect.ValueOf(value)
+		}
+	}
+	except := make(map[int]struct{}, len(mapGroupValues))
+	for i := range mapGroupValues {
+		except[i] = struct{}{}
+	}
+	f, err := makeExtractIntoFunc(structPtr, &c.Container, except)
+	if err != nil {
+		return wrapError("ExtractInto", err)
+	}
+	err = internal.WrapErrorWithLocationFix(internal.LocationFixOption{PC: pc}, func(uintptr) error { return c.Invoke(f) })
+	if err != nil {
+		return err
+	}
+	structValue := reflect.ValueOf(structPtr).Elem()
+	for i, value := range mapGroupValues {
+		internal.EnsureValueExported(structValue.Field(i)).Set(value)
+	}
+	return nil
+}
+
+// IsMissingDependency return true if err (or the error it wraps) is caused by a type not provided to the container,
+// include the missing dependencies of constructors.
+//
+// for example
+//   c := digpro.New()
+//   _, err := c.Extract(int(0))
+//   fmt.Println(digpro.IsMissingDependency(err))
+//   // Output: true
+func IsMissingDependency(err error) bool {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if internal.IsDigErrMissingDependencies(err) || internal.IsDigErrMissingTypes(dig.RootCause(err)) {
+			return true
+		}
+	}
+	return false
 }
@@ -31,3 +31,58 @@ func ExampleMakeExtractFunc() {
 	fmt.Println(*i == 1)
 	// Output: true
 }
+
+func ExampleExtractOptional() {
+	c := dig.New()
+	_ = c.Provide(func() int { return 1 }) // please handle error in production
+	i, found, _ := digpro.ExtractOptional(c, int(0))
+	s, found2, _ := digpro.ExtractOptional(c, "")
+	fmt.Printf("%v %v %q %v\n", i, found, s, found2)
+	// Output: 1 true "" false
+}
+
+func ExampleContainerWrapper_ExtractOptional() {
+	c := digpro.New()
+	_ = c.Supply(1) // please handle error in production
+	i, found, _ := c.ExtractOptional(int(0))
+	s, found2, _ := c.ExtractOptional("")
+	fmt.Printf("%v %v %q %v\n", i, found, s, found2)
+	// Output: 1 true "" false
+}
+
+func ExampleIsMissingDependency() {
+	c := digpro.New()
+	_, err := c.Extract(int(0))
+	fmt.Println(digpro.IsMissingDependency(err))
+	// Output: true
+}
+
+func ExampleExtractInto() {
+	type Deps struct {
+		A string
+		B int  `name:"b"`
+		C bool `optional:"true"`
+	}
+	c := dig.New()
+	_ = c.Provide(func() string { return "a" }) // please handle error in production
+	_ = c.Provide(func() int { return 1 }, dig.Name("b"))
+	var deps Deps
+	_ = digpro.ExtractInto(c, &deps)
+	fmt.Printf("%#v\n", deps)
+	// Output: digpro_test.Deps{A:"a", B:1, C:false}
+}
+
+func ExampleContainerWrapper_ExtractInto() {
+	type Deps struct {
+		A string
+		B int  `name:"b"`
+		C bool `optional:"true"`
+	}
+	c := digpro.New()
+	_ = c.Supply("a") // please handle error in production
+	_ = c.Supply(1, dig.Name("b"))
+	var deps Deps
+	_ = c.ExtractInto(&deps)
+	fmt.Printf("%#v\n", deps)
+	// Output: digpro_test.Deps{A:"a", B:1, C:false}
+}
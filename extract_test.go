@@ -1,10 +1,14 @@
 package digpro
 
 import (
+	"errors"
+	"fmt"
 	"reflect"
+	"runtime"
 	"strings"
 	"testing"
 
+	"github.com/rectcircle/digpro/internal"
 	"github.com/rectcircle/digpro/internal/tests"
 	"go.uber.org/dig"
 )
@@ -163,3 +167,373 @@ func TestMakeExtractFunc(t *testing.T) {
 		})
 	}
 }
+
+var testExtractOptionalData = []struct {
+	name           string
+	args           testExtractArgs
+	want           interface{}
+	wantFound      bool
+	wantErr        bool
+	wantErrContain string
+}{
+	{
+		name: "not found",
+		args: testExtractArgs{
+			prepare: tests.ProviderSet(),
+			typ:     1,
+		},
+		want:      0,
+		wantFound: false,
+	},
+	{
+		name: "not found interface",
+		args: testExtractArgs{
+			prepare: tests.ProviderSet(),
+			typ:     new(I1),
+		},
+		want:      nil,
+		wantFound: false,
+	},
+	{
+		name: "not found name",
+		args: testExtractArgs{
+			prepare: tests.ProviderOne(Supply(1)),
+			typ:     1,
+			opts:    []ExtractOption{ExtractByName("a")},
+		},
+		want:      0,
+		wantFound: false,
+	},
+	{
+		name: "found",
+		args: testExtractArgs{
+			prepare: tests.ProviderOne(Supply(1), dig.Name("a")),
+			typ:     1,
+			opts:    []ExtractOption{ExtractByName("a")},
+		},
+		want:      1,
+		wantFound: true,
+	},
+	{
+		name: "error dependency of constructor missing",
+		args: testExtractArgs{
+			prepare: tests.ProviderOne(func(s string) int { return 1 }),
+			typ:     1,
+		},
+		wantErr:        true,
+		wantErrContain: "missing dependencies",
+	},
+}
+
+func TestExtractOptional(t *testing.T) {
+	for _, tt := range testExtractOptionalData {
+		t.Run(tt.name, func(t *testing.T) {
+			c := dig.New()
+			if err := tt.args.prepare.Apply(c.Provide); err != nil {
+				t.Errorf("prepare error = %v", err)
+				return
+			}
+			got, found, err := ExtractOptional(c, tt.args.typ, tt.args.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ExtractOptional() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				if !strings.Contains(err.Error(), tt.wantErrContain) {
+					t.Errorf("ExtractOptional() error = %v, want contain = %s", err, tt.wantErrContain)
+				}
+				if !IsMissingDependency(err) {
+					t.Errorf("IsMissingDependency() = false, want true")
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) || found != tt.wantFound {
+				t.Errorf("ExtractOptional() = %v, %v, want %v, %v", got, found, tt.want, tt.wantFound)
+			}
+		})
+	}
+}
+
+func TestContainerWrapper_ExtractOptional(t *testing.T) {
+	for _, tt := range testExtractOptionalData {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+			if err := tt.args.prepare.Apply(c.Provide); err != nil {
+				t.Errorf("prepare error = %v", err)
+				return
+			}
+			got, found, err := c.ExtractOptional(tt.args.typ, tt.args.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ContainerWrapper.ExtractOptional() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				if !strings.Contains(err.Error(), tt.wantErrContain) {
+					t.Errorf("ContainerWrapper.ExtractOptional() error = %v, want contain = %s", err, tt.wantErrContain)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) || found != tt.wantFound {
+				t.Errorf("ContainerWrapper.ExtractOptional() = %v, %v, want %v, %v", got, found, tt.want, tt.wantFound)
+			}
+		})
+	}
+}
+
+func TestIsMissingDependency(t *testing.T) {
+	c := New()
+	_, err := c.Extract(1)
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "other error", err: errors.New("a"), want: false},
+		{name: "missing dependency", err: err, want: true},
+		{name: "wrapped missing dependency", err: fmt.Errorf("wrap: %w", err), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsMissingDependency(tt.err); got != tt.want {
+				t.Errorf("IsMissingDependency() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type testExtractIntoTarget struct {
+	A      string
+	B      int      `name:"b"`
+	C      []string `group:"c"`
+	D      bool     `optional:"true"`
+	E      int      `name:"e" optional:"true" default:"2"`
+	F      int      `digpro:"ignore"`
+	g      string
+	Ignore string `digpro:"ignore"`
+}
+
+var testExtractIntoData = []struct {
+	name           string
+	prepare        tests.Provider
+	structPtr      interface{}
+	want           interface{}
+	wantErr        bool
+	wantErrContain string
+}{
+	{
+		name: "success",
+		prepare: tests.ProviderSet(
+			tests.ProviderOne(Supply("a")),
+			tests.ProviderOne(Supply(1), dig.Name("b")),
+			tests.ProviderOne(Supply("c1"), dig.Group("c")),
+		),
+		structPtr: &testExtractIntoTarget{F: 4, Ignore: "ignore"},
+		want:      &testExtractIntoTarget{A: "a", B: 1, C: []string{"c1"}, E: 2, F: 4, g: "a", Ignore: "ignore"},
+	},
+	{
+		name: "error missing dependencies and target not changed",
+		prepare: tests.ProviderSet(
+			tests.ProviderOne(Supply("a")),
+		),
+		structPtr:      &testExtractIntoTarget{F: 4},
+		want:           &testExtractIntoTarget{F: 4},
+		wantErr:        true,
+		wantErrContain: tests.GetSelfSourceCodeFilePath(),
+	},
+	{
+		name:           "error not pointer",
+		prepare:        tests.ProviderSet(),
+		structPtr:      testExtractIntoTarget{},
+		want:           testExtractIntoTarget{},
+		wantErr:        true,
+		wantErrContain: "[ExtractInto] structPtr want non nil struct pointer",
+	},
+	{
+		name:           "error nil",
+		prepare:        tests.ProviderSet(),
+		structPtr:      (*testExtractIntoTarget)(nil),
+		want:           (*testExtractIntoTarget)(nil),
+		wantErr:        true,
+		wantErrContain: "[ExtractInto] structOrStructPtr want struct or non nil struct pointer",
+	},
+	{
+		name:    "error invalid default",
+		prepare: tests.ProviderSet(),
+		structPtr: &struct {
+			A int `default:"1"`
+		}{},
+		want: &struct {
+			A int `default:"1"`
+		}{},
+		wantErr:        true,
+		wantErrContain: "[ExtractInto] field A:int has default tag",
+	},
+}
+
+func TestExtractInto(t *testing.T) {
+	for _, tt := range testExtractIntoData {
+		t.Run(tt.name, func(t *testing.T) {
+			c := dig.New()
+			if err := tt.prepare.Apply(c.Provide); err != nil {
+				t.Errorf("prepare error = %v", err)
+				return
+			}
+			err := ExtractInto(c, tt.structPtr)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ExtractInto() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil && !strings.Contains(err.Error(), tt.wantErrContain) {
+				t.Errorf("ExtractInto() error = %v, want contain = %s", err, tt.wantErrContain)
+			}
+			if !reflect.DeepEqual(tt.structPtr, tt.want) {
+				t.Errorf("structPtr = %#v, want %#v", tt.structPtr, tt.want)
+			}
+		})
+	}
+}
+
+func TestContainerWrapper_ExtractInto(t *testing.T) {
+	for _, tt := range testExtractIntoData {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+			if err := tt.prepare.Apply(c.Provide); err != nil {
+				t.Errorf("prepare error = %v", err)
+				return
+			}
+			err := c.ExtractInto(tt.structPtr)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ContainerWrapper.ExtractInto() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil && !strings.Contains(err.Error(), tt.wantErrContain) {
+				t.Errorf("ContainerWrapper.ExtractInto() error = %v, want contain = %s", err, tt.wantErrContain)
+			}
+			if !reflect.DeepEqual(tt.structPtr, tt.want) {
+				t.Errorf("structPtr = %#v, want %#v", tt.structPtr, tt.want)
+			}
+		})
+	}
+}
+
+func TestContainerWrapper_ExtractInto_options(t *testing.T) {
+	pc, _, _, _ := runtime.Caller(0)
+	tests := []struct {
+		name           string
+		opts           []ExtractOption
+		wantErrContain string
+	}{
+		{
+			name:           "location fix pc",
+			opts:           []ExtractOption{internal.LocationFixOption{PC: pc}},
+			wantErrContain: "TestContainerWrapper_ExtractInto_options (" + tests.GetSelfSourceCodeFilePath(),
+		},
+		{
+			name:           "error name",
+			opts:           []ExtractOption{ExtractByName("a")},
+			wantErrContain: "[ExtractInto] name and group options are not supported",
+		},
+		{
+			name:           "error group ordered",
+			opts:           []ExtractOption{ExtractByGroup("g"), ExtractGroupOrdered()},
+			wantErrContain: "[ExtractInto] name and group options are not supported",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+			target := struct{ A int }{}
+			err := c.ExtractInto(&target, tt.opts...)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErrContain) {
+				t.Errorf("ContainerWrapper.ExtractInto() error = %v, want contain = %s", err, tt.wantErrContain)
+			}
+		})
+	}
+}
+
+func TestContainerWrapper_ExtractOptional_ExtractInto_group(t *testing.T) {
+	type into struct {
+		A string            `name:"a"`
+		M map[string]string `group:"g"`
+	}
+	prepare := func(c *ContainerWrapper) error {
+		return firstError(
+			c.Supply("a", dig.Name("a")),
+			c.Supply("x", dig.Group("g"), GroupMemberName("X"), GroupPriority(1)),
+			c.Supply("y", dig.Group("g"), GroupMemberName("Y"), GroupPriority(2)),
+		)
+	}
+	tests := []struct {
+		name    string
+		extract func(c *ContainerWrapper) (interface{}, bool, error)
+		want    interface{}
+		wantErr bool
+	}{
+		{
+			name: "optional map",
+			extract: func(c *ContainerWrapper) (interface{}, bool, error) {
+				return c.ExtractOptional(map[string]string{}, ExtractByGroup("g"))
+			},
+			want: map[string]string{"X": "x", "Y": "y"},
+		},
+		{
+			name: "optional ordered",
+			extract: func(c *ContainerWrapper) (interface{}, bool, error) {
+				return c.ExtractOptional([]string{}, ExtractByGroup("g"), ExtractGroupOrdered())
+			},
+			want: []string{"y", "x"},
+		},
+		{
+			name: "optional empty group",
+			extract: func(c *ContainerWrapper) (interface{}, bool, error) {
+				return c.ExtractOptional(map[string]int{}, ExtractByGroup("g"))
+			},
+			want: map[string]int{},
+		},
+		{
+			name: "into map",
+			extract: func(c *ContainerWrapper) (interface{}, bool, error) {
+				got := into{}
+				err := c.ExtractInto(&got)
+				return got, err == nil, err
+			},
+			want: into{A: "a", M: map[string]string{"X": "x", "Y": "y"}},
+		},
+		{
+			name: "into map error",
+			extract: func(c *ContainerWrapper) (interface{}, bool, error) {
+				got := struct {
+					M map[string]string `group:"g"`
+					B bool
+				}{}
+				err := c.ExtractInto(&got)
+				if got.M != nil {
+					t.Errorf("got.M = %v, want not changed", got.M)
+				}
+				return nil, false, err
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+			if err := prepare(c); err != nil {
+				t.Errorf("prepare() error = %v", err)
+				return
+			}
+			got, found, err := tt.extract(c)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("extract() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if !found || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("extract() = %#v, %v, want %#v, true", got, found, tt.want)
+			}
+		})
+	}
+}
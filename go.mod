@@ -1,5 +1,5 @@
 module github.com/rectcircle/digpro
 
-go 1.13
+go 1.18
 
 require go.uber.org/dig v1.13.0
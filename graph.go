@@ -0,0 +1,204 @@
+package digpro
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/rectcircle/digpro/internal"
+)
+
+// GraphFormat is the format of ExportGraph
+type GraphFormat string
+
+const (
+	// GraphFormatJSON export graph as JSON, the schema see digpro.Graph
+	GraphFormatJSON GraphFormat = "json"
+)
+
+// Graph is the dependency graph of providers, which is exported by ExportGraph
+type Graph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// GraphNode is a provider which is not removed, ID is same as digpro.ProviderDescriptor.ID
+type GraphNode struct {
+	ID            int           `json:"id"`
+	Kind          string        `json:"kind"`
+	Location      GraphLocation `json:"location"`
+	Outputs       []GraphOutput `json:"outputs"`
+	Override      bool          `json:"override"`      // the provider overrides other providers
+	ResolveCyclic bool          `json:"resolveCyclic"` // the provider is registered with digpro.ResolveCyclic()
+}
+
+// GraphLocation is the source location of provider
+type GraphLocation struct {
+	Package  string `json:"package"`
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// GraphOutput is the output key of provider
+type GraphOutput struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Group string `json:"group"`
+}
+
+// GraphInput is the input key of provider
+type GraphInput struct {
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	Group    string `json:"group"`
+	Optional bool   `json:"optional"`
+}
+
+// GraphEdge means the provider From depends on the provider To by Input
+type GraphEdge struct {
+	From          int        `json:"from"`
+	To            int        `json:"to"`
+	Input         GraphInput `json:"input"`
+	Lazy          bool       `json:"lazy"`          // Input is digpro.Lazy[T], To provide T
+	ResolveCyclic bool       `json:"resolveCyclic"` // Input is injected after construct by digpro.ResolveCyclic()
+}
+
+// dependencyEdge is the edge of provider dependency graph, provideInfos[from] depends on provideInfos[to] by input
+type dependencyEdge struct {
+	from, to      int
+	input         internal.ProvideInput
+	lazy          bool // input is digpro.Lazy[T], provideInfos[to] provide T
+	resolveCyclic bool // input is injected after construct by digpro.ResolveCyclic
+}
+
+// dependencyGraph return the adjacency list of the providers which are not removed, key is the index of provideInfos.
+// if deferred is true, the fields of digpro.ResolveCyclic Struct and digpro.Lazy inputs are included,
+// which are injected after construct
+func (c *ContainerWrapper) dependencyGraph(deferred bool) map[int][]dependencyEdge {
+	providers := make(map[internal.ProvideOutput][]int)
+	for i := range c.provideInfos {
+		if c.provideInfos[i].Removed {
+			continue
+		}
+		for _, output := range c.provideInfos[i].ExportedOutputs() {
+			providers[output] = append(providers[output], i)
+		}
+	}
+	graph := make(map[int][]dependencyEdge)
+	for i := range c.provideInfos {
+		info := &c.provideInfos[i]
+		if info.Removed {
+			continue
+		}
+		inputs := info.ExportedInputs()
+		if deferred {
+			// the fields of digpro.ResolveCyclic Struct are appended after inputs
+			inputs = c.providerInputs(info)
+		}
+		edges := []dependencyEdge{}
+		for k, input := range inputs {
+			output := internal.ProvideOutput{Type: input.Type, Name: input.Name}
+			lazy := false
+			if input.Group != "" {
+				// the type of value group input is slice
+				output = internal.ProvideOutput{Type: input.Type.Elem(), Group: input.Group}
+			} else if elemOutput, ok := lazyElemOutput(input); deferred && ok {
+				output = elemOutput
+				lazy = true
+			}
+			for _, j := range providers[output] {
+				edges = append(edges, dependencyEdge{
+					from:          i,
+					to:            j,
+					input:         input,
+					lazy:          lazy,
+					resolveCyclic: k >= len(info.ExportedInputs()),
+				})
+			}
+		}
+		graph[i] = edges
+	}
+	return graph
+}
+
+// graph return the dependency graph of providers which are not removed
+func (c *ContainerWrapper) graph() Graph {
+	c.syncParent()
+	dependencies := c.dependencyGraph(true)
+	graph := Graph{Nodes: []GraphNode{}, Edges: []GraphEdge{}}
+	for i := range c.provideInfos {
+		info := &c.provideInfos[i]
+		if info.Removed {
+			continue
+		}
+		node := GraphNode{
+			ID:            i,
+			Kind:          info.Kind.String(),
+			Outputs:       []GraphOutput{},
+			Override:      len(info.Overrides) != 0,
+			ResolveCyclic: info.ResolveCyclic,
+		}
+		if location := info.Location(); location != nil {
+			node.Location = GraphLocation{Package: location.Package, Function: location.Name, File: location.File, Line: location.Line}
+		}
+		for _, output := range info.ExportedOutputs() {
+			node.Outputs = append(node.Outputs, GraphOutput{Type: output.Type.String(), Name: output.Name, Group: output.Group})
+		}
+		graph.Nodes = append(graph.Nodes, node)
+		for _, edge := range dependencies[i] {
+			graph.Edges = append(graph.Edges, GraphEdge{
+				From:          edge.from,
+				To:            edge.to,
+				Input:         GraphInput{Type: edge.input.Type.String(), Name: edge.input.Name, Group: edge.input.Group, Optional: edge.input.Optional},
+				Lazy:          edge.lazy,
+				ResolveCyclic: edge.resolveCyclic,
+			})
+		}
+	}
+	return graph
+}
+
+// ExportGraph write the dependency graph of providers (not include removed) to w in format,
+// GraphFormatJSON is machine-readable and stable, can be used to diff dependency graphs between releases.
+// GraphFormatDOT, GraphFormatMermaid and GraphFormatPlantUML can be rendered or embedded in documents,
+// see digpro.GraphOption for options (GraphRenderAs and GraphReverseDependents are ignored).
+// Nodes and edges are in registration order.
+//
+// for example
+//   c := digpro.New()
+//   _ = c.Supply(1) // please handle error in production
+//   _ = c.Provide(func(i int) string { return fmt.Sprint(i) })
+//   _ = c.ExportGraph(os.Stdout, digpro.GraphFormatJSON)
+//   // Output:
+//   // {
+//   //   "nodes": [
+//   //     {
+//   //       "id": 0,
+//   //       "kind": "Supply",
+//   // ...
+func (c *ContainerWrapper) ExportGraph(w io.Writer, format GraphFormat, opts ...GraphOption) error {
+	options := graphOptions{}
+	for _, opt := range opts {
+		opt.applyGraphOption(&options)
+	}
+	return c.graph().write(w, format, options, "ExportGraph")
+}
+
+// write the graph to w in format, the unsupported format error is prefixed by errPrefix
+func (graph Graph) write(w io.Writer, format GraphFormat, opts graphOptions, errPrefix string) error {
+	switch format {
+	case GraphFormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(graph)
+	case GraphFormatDOT:
+		return graph.view(opts).writeDOT(w)
+	case GraphFormatMermaid:
+		return graph.view(opts).writeMermaid(w)
+	case GraphFormatPlantUML:
+		return graph.view(opts).writePlantUML(w)
+	default:
+		return wrapError(errPrefix, fmt.Errorf("unsupported format %q", format))
+	}
+}
@@ -0,0 +1,29 @@
+package digpro_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/rectcircle/digpro"
+)
+
+func ExampleContainerWrapper_ExportGraph() {
+	c := digpro.New()
+	_ = c.Supply(1) // please handle error in production
+	_ = c.Provide(func(i int) string { return fmt.Sprint(i) })
+	buf := bytes.Buffer{}
+	_ = c.ExportGraph(&buf, digpro.GraphFormatJSON)
+	graph := digpro.Graph{}
+	_ = json.Unmarshal(buf.Bytes(), &graph)
+	for _, node := range graph.Nodes {
+		fmt.Println(node.ID, node.Kind, node.Outputs[0].Type)
+	}
+	for _, edge := range graph.Edges {
+		fmt.Println(edge.From, "->", edge.To, edge.Input.Type)
+	}
+	// Output:
+	// 0 Supply int
+	// 1 Provide string
+	// 1 -> 0 int
+}
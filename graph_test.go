@@ -0,0 +1,153 @@
+package digpro
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.uber.org/dig"
+)
+
+func TestContainerWrapper_ExportGraph(t *testing.T) {
+	tests := []struct {
+		name           string
+		prepare        PrepareFunc
+		format         GraphFormat
+		wantNodes      []GraphNode // Location is ignored
+		wantEdges      []GraphEdge
+		wantErr        bool
+		wantErrContain string
+	}{
+		{
+			name: "provide and struct",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Supply("a", dig.Name("a")),
+					c.Provide(func(s string) int { return 1 }, dig.Name("b")),
+					c.Struct(new(verifyFoo)),
+				)
+			},
+			format: GraphFormatJSON,
+			wantNodes: []GraphNode{
+				{ID: 0, Kind: "Supply", Outputs: []GraphOutput{{Type: "string", Name: "a"}}},
+				{ID: 1, Kind: "Provide", Outputs: []GraphOutput{{Type: "int", Name: "b"}}},
+				{ID: 2, Kind: "Struct", Outputs: []GraphOutput{{Type: "*digpro.verifyFoo"}}},
+			},
+			wantEdges: []GraphEdge{
+				{From: 2, To: 0, Input: GraphInput{Type: "string", Name: "a"}},
+				{From: 2, To: 1, Input: GraphInput{Type: "int", Name: "b"}},
+			},
+		},
+		{
+			name: "optional and value group",
+			prepare: func(c *ContainerWrapper) error {
+				type in struct {
+					dig.In
+					S []string `group:"g"`
+					I int      `optional:"true"`
+				}
+				return firstError(
+					c.Supply("a", dig.Group("g")),
+					c.Supply("b", dig.Group("g")),
+					c.Supply(1),
+					c.Provide(func(i in) bool { return true }),
+				)
+			},
+			format: GraphFormatJSON,
+			wantNodes: []GraphNode{
+				{ID: 0, Kind: "Supply", Outputs: []GraphOutput{{Type: "string", Group: "g"}}},
+				{ID: 1, Kind: "Supply", Outputs: []GraphOutput{{Type: "string", Group: "g"}}},
+				{ID: 2, Kind: "Supply", Outputs: []GraphOutput{{Type: "int"}}},
+				{ID: 3, Kind: "Provide", Outputs: []GraphOutput{{Type: "bool"}}},
+			},
+			wantEdges: []GraphEdge{
+				{From: 3, To: 0, Input: GraphInput{Type: "[]string", Group: "g"}},
+				{From: 3, To: 1, Input: GraphInput{Type: "[]string", Group: "g"}},
+				{From: 3, To: 2, Input: GraphInput{Type: "int", Optional: true}},
+			},
+		},
+		{
+			name: "override and resolve cyclic",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Supply(1),
+					c.Supply("a"),
+					c.Supply(2, Override()),
+					c.Struct(new(D1), ResolveCyclic()),
+					c.Struct(new(D2)),
+				)
+			},
+			format: GraphFormatJSON,
+			wantNodes: []GraphNode{
+				{ID: 1, Kind: "Supply", Outputs: []GraphOutput{{Type: "string"}}},
+				{ID: 2, Kind: "Supply", Outputs: []GraphOutput{{Type: "int"}}, Override: true},
+				{ID: 3, Kind: "Struct", Outputs: []GraphOutput{{Type: "*digpro.D1"}}, ResolveCyclic: true},
+				{ID: 4, Kind: "Struct", Outputs: []GraphOutput{{Type: "*digpro.D2"}}},
+			},
+			wantEdges: []GraphEdge{
+				{From: 3, To: 4, Input: GraphInput{Type: "*digpro.D2"}, ResolveCyclic: true},
+				{From: 3, To: 2, Input: GraphInput{Type: "int"}, ResolveCyclic: true},
+				{From: 4, To: 3, Input: GraphInput{Type: "*digpro.D1"}},
+				{From: 4, To: 1, Input: GraphInput{Type: "string"}},
+			},
+		},
+		{
+			name: "empty",
+			prepare: func(c *ContainerWrapper) error {
+				return nil
+			},
+			format:    GraphFormatJSON,
+			wantNodes: []GraphNode{},
+			wantEdges: []GraphEdge{},
+		},
+		{
+			name: "error unsupported format",
+			prepare: func(c *ContainerWrapper) error {
+				return nil
+			},
+			format:         "unknown",
+			wantErr:        true,
+			wantErrContain: "[ExportGraph] unsupported format \"unknown\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+			if err := tt.prepare(c); err != nil {
+				t.Errorf("prepare() error = %v", err)
+				return
+			}
+			buf := bytes.Buffer{}
+			err := c.ExportGraph(&buf, tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("c.ExportGraph() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				if !strings.Contains(err.Error(), tt.wantErrContain) {
+					t.Errorf("c.ExportGraph() error = %v, wantErrContain %s", err, tt.wantErrContain)
+				}
+				return
+			}
+			got := Graph{}
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Errorf("json.Unmarshal() error = %v", err)
+				return
+			}
+			for i := range got.Nodes {
+				if !strings.HasSuffix(got.Nodes[i].Location.File, "graph_test.go") {
+					t.Errorf("got.Nodes[%d].Location = %+v, want in graph_test.go", i, got.Nodes[i].Location)
+				}
+				got.Nodes[i].Location = GraphLocation{}
+			}
+			if !reflect.DeepEqual(got.Nodes, tt.wantNodes) {
+				t.Errorf("c.ExportGraph() nodes = %+v, want %+v", got.Nodes, tt.wantNodes)
+			}
+			if !reflect.DeepEqual(got.Edges, tt.wantEdges) {
+				t.Errorf("c.ExportGraph() edges = %+v, want %+v", got.Edges, tt.wantEdges)
+			}
+		})
+	}
+}
@@ -0,0 +1,301 @@
+package digpro
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+
+	"github.com/rectcircle/digpro/internal"
+	"github.com/rectcircle/digpro/internal/digcopy"
+	"go.uber.org/dig"
+)
+
+type groupPriorityProvideOption struct {
+	dig.ProvideOption
+	priority int
+}
+
+type groupMemberNameProvideOption struct {
+	dig.ProvideOption
+	name string
+}
+
+// groupBridgeProvideOption mark the provider is a bridge of parent value group, see Scope
+type groupBridgeProvideOption struct {
+	dig.ProvideOption
+}
+
+var groupPriorityProvideOptionType = reflect.TypeOf(groupPriorityProvideOption{})
+var groupMemberNameProvideOptionType = reflect.TypeOf(groupMemberNameProvideOption{})
+
+// groupMemberOptions is the options of a value group provider
+type groupMemberOptions struct {
+	priority int
+	name     string
+	bridge   bool
+}
+
+// groupValuesRecord record the values of output produced by provideInfos[info] start at dig.Container.groups[key][start]
+type groupValuesRecord struct {
+	info  int
+	start int
+}
+
+type groupMember struct {
+	value reflect.Value
+	groupMemberOptions
+}
+
+// GroupPriority set the priority of value group members provided by the constructor,
+// when extract with digpro.ExtractGroupOrdered(), the member with higher priority comes first,
+// and the members with same priority are sorted by registration order. Default priority is 0.
+//
+// for example
+//   c := digpro.New()
+//   _ = c.Supply("a", dig.Group("g")) // please handle error in production
+//   _ = c.Supply("b", dig.Group("g"), digpro.GroupPriority(1))
+//   _ = c.Supply("c", dig.Group("g"))
+//   s, _ := c.Extract([]string{}, digpro.ExtractByGroup("g"), digpro.ExtractGroupOrdered())
+//   fmt.Println(s)
+//   // Output: [b a c]
+func GroupPriority(priority int) dig.ProvideOption {
+	return groupPriorityProvideOption{priority: priority}
+}
+
+// GroupMemberName set the name of value group members provided by the constructor,
+// which will be used as key, when extract value group into map[string]T.
+//
+// for example
+//   c := digpro.New()
+//   _ = c.Supply("a", dig.Group("g"), digpro.GroupMemberName("A")) // please handle error in production
+//   _ = c.Supply("b", dig.Group("g"), digpro.GroupMemberName("B"))
+//   m, _ := c.Extract(map[string]string{}, digpro.ExtractByGroup("g"))
+//   fmt.Println(m)
+//   // Output: map[A:a B:b]
+func GroupMemberName(name string) dig.ProvideOption {
+	return groupMemberNameProvideOption{name: name}
+}
+
+// ExtractGroupOrdered sort the value group members by priority (see digpro.GroupPriority) and registration order,
+// only support *digpro.ContainerWrapper, should be used with digpro.ExtractByGroup.
+//
+// for example
+//   c := digpro.New()
+//   _ = c.Supply("a", dig.Group("g")) // please handle error in production
+//   _ = c.Supply("b", dig.Group("g"))
+//   s, _ := c.Extract([]string{}, digpro.ExtractByGroup("g"), digpro.ExtractGroupOrdered())
+//   fmt.Println(s)
+//   // Output: [a b]
+func ExtractGroupOrdered() ExtractOption {
+	return internal.ExtractOptionFunc(func(opts *internal.ExtractOptions) {
+		opts.GroupOrdered = true
+	})
+}
+
+// GroupKeyer can be implemented by the value group member,
+// GroupKey will be used as key, when extract value group into map[string]T and digpro.GroupMemberName is not set.
+type GroupKeyer interface {
+	GroupKey() string
+}
+
+// hasGroupResult return true if the constructor may produce value group member
+func hasGroupResult(constructor interface{}, opts []dig.ProvideOption) bool {
+	if internal.ApplyProvideOptions(opts...).Group != "" {
+		return true
+	}
+	ft := reflect.TypeOf(constructor)
+	if ft == nil || ft.Kind() != reflect.Func {
+		return false
+	}
+	resultTypes := []reflect.Type{}
+	for i := 0; i < ft.NumOut(); i++ {
+		resultTypes = append(resultTypes, ft.Out(i))
+	}
+	for len(resultTypes) != 0 {
+		t := resultTypes[0]
+		resultTypes = resultTypes[1:]
+		if !dig.IsOut(t) {
+			continue
+		}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.Tag.Get(internal.DigGroupTag) != "" {
+				return true
+			}
+			if f.PkgPath == "" && !(f.Anonymous && f.Type == reflect.TypeOf(dig.Out{})) {
+				resultTypes = append(resultTypes, f.Type)
+			}
+		}
+	}
+	return false
+}
+
+// groupProvideMiddleware record the values produced by value group provider, for ExtractGroupOrdered and map extract
+func groupProvideMiddleware(pc *provideContext) error {
+	var (
+		options groupMemberOptions
+		hasOpt  = false
+		_opts   = make([]dig.ProvideOption, 0, len(pc.opts))
+	)
+	for _, opt := range pc.opts {
+		if o, ok := opt.(groupPriorityProvideOption); ok {
+			options.priority = o.priority
+			hasOpt = true
+		} else if o, ok := opt.(groupMemberNameProvideOption); ok {
+			options.name = o.name
+			hasOpt = true
+		} else if _, ok := opt.(groupBridgeProvideOption); ok {
+			options.bridge = true
+		} else {
+			_opts = append(_opts, opt)
+		}
+	}
+	pc.opts = _opts
+	if !hasGroupResult(pc.constructor, pc.opts) {
+		if hasOpt {
+			return errors.New("digpro.GroupPriority() and digpro.GroupMemberName() only support value groups")
+		}
+		return pc.next()
+	}
+	index := -1
+	err := pc.wrapConstructor(func([]reflect.Value, *digcopy.Func) {
+		pc.c.recordGroupValues(index)
+	})
+	if err != nil {
+		return err
+	}
+	index = len(pc.c.provideInfos) - 1
+	pc.c.groupMemberOptions[index] = options
+	return nil
+}
+
+// digGroupValues return dig.Container.groups[key] of output, the values are in the order of submitted
+func (c *ContainerWrapper) digGroupValues(output internal.ProvideOutput) reflect.Value {
+	containerValue := reflect.ValueOf(&c.Container).Elem()
+	groupsValue := internal.EnsureValueExported(containerValue.FieldByName("groups")) // map[dig.key][]reflect.Value
+
+	key := reflect.New(groupsValue.Type().Key()).Elem()
+	internal.EnsureValueExported(key.FieldByName("t")).Set(reflect.ValueOf(output.Type))
+	internal.EnsureValueExported(key.FieldByName("group")).Set(reflect.ValueOf(output.Group))
+
+	values := groupsValue.MapIndex(key)
+	if !values.IsValid() {
+		return reflect.MakeSlice(groupsValue.Type().Elem(), 0, 0)
+	}
+	return values
+}
+
+// recordGroupValues is called when the constructor of provideInfos[info] is called,
+// at this time, the values of constructor have not been submitted to dig.Container.groups
+func (c *ContainerWrapper) recordGroupValues(info int) {
+	for _, output := range c.provideInfos[info].ExportedOutputs() {
+		if output.Group == "" {
+			continue
+		}
+		c.groupValuesRecords[output] = append(c.groupValuesRecords[output], groupValuesRecord{
+			info:  info,
+			start: c.digGroupValues(output).Len(),
+		})
+	}
+}
+
+// groupMembers return the members of output (must has been built by dig) in registration order
+func (c *ContainerWrapper) groupMembers(output internal.ProvideOutput) []groupMember {
+	values := c.digGroupValues(output).Interface().([]reflect.Value)
+	records := c.groupValuesRecords[output]
+	// the values not recorded (e.g. provided by *dig.Container directly), treat as registered first
+	if len(records) == 0 || records[0].start != 0 {
+		records = append([]groupValuesRecord{{info: -1, start: 0}}, records...)
+	}
+	type segment struct {
+		info   int
+		values []reflect.Value
+	}
+	segments := make([]segment, 0, len(records))
+	for i, record := range records {
+		end := len(values)
+		if i+1 < len(records) {
+			end = records[i+1].start
+		}
+		segments = append(segments, segment{info: record.info, values: values[record.start:end]})
+	}
+	sort.SliceStable(segments, func(i, j int) bool {
+		return segments[i].info < segments[j].info
+	})
+
+	members := make([]groupMember, 0, len(values))
+	for _, s := range segments {
+		options := c.groupMemberOptions[s.info]
+		if options.bridge && c.parent != nil {
+			members = append(members, c.parent.groupMembers(output)...)
+			continue
+		}
+		for _, v := range s.values {
+			members = append(members, groupMember{value: v, groupMemberOptions: options})
+		}
+	}
+	return members
+}
+
+// extract is the implementation of Extract, support ExtractGroupOrdered and extract value group into map[string]T,
+// callSkip is used to get the location of caller by runtime.Caller(callSkip)
+func (c *ContainerWrapper) extract(callSkip int, typ interface{}, opts ...ExtractOption) (interface{}, error) {
+	options := internal.ApplyExtractOptions(opts...)
+	fix := internal.LocationFixOption{CallSkip: callSkip}
+	if options.LocationFix != nil {
+		fix = *options.LocationFix
+	}
+	if fix.PC == 0 {
+		fix.PC, _, _, _ = runtime.Caller(fix.CallSkip)
+	}
+	fix.CallSkip = 0
+	if typ == nil || options.Group == "" {
+		return internal.ExtractWithLocationFix(c.Invoke, fix, typ, opts...)
+	}
+	t := internal.ExtractTypeOf(typ)
+	if !options.GroupOrdered && t.Kind() != reflect.Map {
+		return internal.ExtractWithLocationFix(c.Invoke, fix, typ, opts...)
+	}
+	if t.Kind() != reflect.Slice && !(t.Kind() == reflect.Map && t.Key().Kind() == reflect.String) {
+		return nil, wrapError("Extract", fmt.Errorf("typ want slice or map[string]T when use digpro.ExtractByGroup, but got %s", t))
+	}
+	// let dig call all providers of the value group
+	output := internal.ProvideOutput{Type: t.Elem(), Group: options.Group}
+	_, err := internal.ExtractWithLocationFix(c.Invoke, fix, reflect.Zero(reflect.SliceOf(output.Type)).Interface(), ExtractByGroup(output.Group))
+	if err != nil {
+		return nil, err
+	}
+	members := c.groupMembers(output)
+	if t.Kind() == reflect.Slice {
+		if options.GroupOrdered {
+			sort.SliceStable(members, func(i, j int) bool {
+				return members[i].priority > members[j].priority
+			})
+		}
+		result := reflect.MakeSlice(t, 0, len(members))
+		for _, m := range members {
+			result = reflect.Append(result, m.value)
+		}
+		return result.Interface(), nil
+	}
+	result := reflect.MakeMapWithSize(t, len(members))
+	for _, m := range members {
+		key := m.name
+		if key == "" {
+			if keyer, ok := m.value.Interface().(GroupKeyer); ok {
+				key = keyer.GroupKey()
+			}
+		}
+		if key == "" {
+			return nil, wrapError("Extract", fmt.Errorf("cannot get key of %s member %#v, please use digpro.GroupMemberName() or implement digpro.GroupKeyer", output.String(), m.value.Interface()))
+		}
+		keyValue := reflect.ValueOf(key).Convert(t.Key())
+		if result.MapIndex(keyValue).IsValid() {
+			return nil, wrapError("Extract", fmt.Errorf("duplicate key %q of %s", key, output.String()))
+		}
+		result.SetMapIndex(keyValue, m.value)
+	}
+	return result.Interface(), nil
+}
@@ -0,0 +1,36 @@
+package digpro_test
+
+import (
+	"fmt"
+
+	"github.com/rectcircle/digpro"
+	"go.uber.org/dig"
+)
+
+func ExampleGroupPriority() {
+	c := digpro.New()
+	_ = c.Supply("a", dig.Group("g")) // please handle error in production
+	_ = c.Supply("b", dig.Group("g"), digpro.GroupPriority(1))
+	_ = c.Supply("c", dig.Group("g"))
+	s, _ := c.Extract([]string{}, digpro.ExtractByGroup("g"), digpro.ExtractGroupOrdered())
+	fmt.Println(s)
+	// Output: [b a c]
+}
+
+func ExampleGroupMemberName() {
+	c := digpro.New()
+	_ = c.Supply("a", dig.Group("g"), digpro.GroupMemberName("A")) // please handle error in production
+	_ = c.Supply("b", dig.Group("g"), digpro.GroupMemberName("B"))
+	m, _ := c.Extract(map[string]string{}, digpro.ExtractByGroup("g"))
+	fmt.Println(m)
+	// Output: map[A:a B:b]
+}
+
+func ExampleExtractGroupOrdered() {
+	c := digpro.New()
+	_ = c.Supply("a", dig.Group("g")) // please handle error in production
+	_ = c.Supply("b", dig.Group("g"))
+	s, _ := c.Extract([]string{}, digpro.ExtractByGroup("g"), digpro.ExtractGroupOrdered())
+	fmt.Println(s)
+	// Output: [a b]
+}
@@ -0,0 +1,233 @@
+package digpro
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.uber.org/dig"
+)
+
+type groupKeyerString string
+
+func (s groupKeyerString) GroupKey() string {
+	return "key-" + string(s)
+}
+
+func TestContainerWrapper_Extract_group(t *testing.T) {
+	tests := []struct {
+		name           string
+		prepare        PrepareFunc
+		wantPrepareErr bool
+		scope          bool
+		extract        interface{}
+		extractOptions []ExtractOption
+		want           interface{}
+		wantErr        bool
+		wantErrContain string
+	}{
+		{
+			name: "ordered by registration",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Supply("a", dig.Group("g")),
+					c.Supply("b", dig.Group("g")),
+					c.Supply("c", dig.Group("g")),
+					c.Supply("d", dig.Group("g")),
+					c.Supply("e", dig.Group("g")),
+				)
+			},
+			extract:        []string{},
+			extractOptions: []ExtractOption{ExtractByGroup("g"), ExtractGroupOrdered()},
+			want:           []string{"a", "b", "c", "d", "e"},
+		},
+		{
+			name: "ordered by priority",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Supply("a", dig.Group("g")),
+					c.Supply("b", dig.Group("g"), GroupPriority(1)),
+					c.Supply("c", dig.Group("g"), GroupPriority(-1)),
+					c.Supply("d", dig.Group("g")),
+					c.Supply("e", dig.Group("g"), GroupPriority(1)),
+				)
+			},
+			extract:        []string{},
+			extractOptions: []ExtractOption{ExtractByGroup("g"), ExtractGroupOrdered()},
+			want:           []string{"b", "e", "a", "d", "c"},
+		},
+		{
+			name: "ordered by registration when called out of order",
+			prepare: func(c *ContainerWrapper) error {
+				type out struct {
+					dig.Out
+					S     []string `group:"g,flatten"`
+					Other int
+				}
+				if err := firstError(
+					c.Supply("a", dig.Group("g")),
+					c.Provide(func() out { return out{S: []string{"b", "c"}, Other: 1} }),
+					c.Supply("d", dig.Group("g")),
+				); err != nil {
+					return err
+				}
+				_, err := c.Extract(int(0))
+				return err
+			},
+			extract:        []string{},
+			extractOptions: []ExtractOption{ExtractByGroup("g"), ExtractGroupOrdered()},
+			want:           []string{"a", "b", "c", "d"},
+		},
+		{
+			name: "ordered struct",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Supply(1),
+					c.Supply(&lifecycleB{Value: 2}, dig.Group("g")),
+					c.Struct(new(lifecycleB), dig.Group("g"), GroupPriority(1)),
+				)
+			},
+			extract:        []*lifecycleB{},
+			extractOptions: []ExtractOption{ExtractByGroup("g"), ExtractGroupOrdered()},
+			want:           []*lifecycleB{{Value: 1}, {Value: 2}},
+		},
+		{
+			name: "map by member name and group keyer",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Supply(groupKeyerString("a"), dig.Group("g"), GroupMemberName("A")),
+					c.Supply(groupKeyerString("b"), dig.Group("g")),
+				)
+			},
+			extract:        map[string]groupKeyerString{},
+			extractOptions: []ExtractOption{ExtractByGroup("g")},
+			want:           map[string]groupKeyerString{"A": "a", "key-b": "b"},
+		},
+		{
+			name: "map of empty group",
+			prepare: func(c *ContainerWrapper) error {
+				return nil
+			},
+			extract:        map[string]string{},
+			extractOptions: []ExtractOption{ExtractByGroup("g")},
+			want:           map[string]string{},
+		},
+		{
+			name: "scope ordered",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.parent.Supply("a", dig.Group("g")),
+					c.parent.Supply("b", dig.Group("g"), GroupPriority(1)),
+					c.Supply("c", dig.Group("g")),
+					c.parent.Supply("d", dig.Group("g")),
+					c.Supply("e", dig.Group("g"), GroupPriority(2)),
+				)
+			},
+			scope:          true,
+			extract:        []string{},
+			extractOptions: []ExtractOption{ExtractByGroup("g"), ExtractGroupOrdered()},
+			want:           []string{"e", "b", "a", "d", "c"},
+		},
+		{
+			name: "scope map",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.parent.Supply("a", dig.Group("g"), GroupMemberName("A")),
+					c.Supply("b", dig.Group("g"), GroupMemberName("B")),
+				)
+			},
+			scope:          true,
+			extract:        map[string]string{},
+			extractOptions: []ExtractOption{ExtractByGroup("g")},
+			want:           map[string]string{"A": "a", "B": "b"},
+		},
+		{
+			name: "error group option without group",
+			prepare: func(c *ContainerWrapper) error {
+				return c.Supply("a", GroupPriority(1))
+			},
+			wantPrepareErr: true,
+		},
+		{
+			name: "error map key missing",
+			prepare: func(c *ContainerWrapper) error {
+				return c.Supply("a", dig.Group("g"))
+			},
+			extract:        map[string]string{},
+			extractOptions: []ExtractOption{ExtractByGroup("g")},
+			wantErr:        true,
+			wantErrContain: "[Extract] cannot get key of string[group=\"g\"] member \"a\"",
+		},
+		{
+			name: "error map key duplicate",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Supply("a", dig.Group("g"), GroupMemberName("A")),
+					c.Supply("b", dig.Group("g"), GroupMemberName("A")),
+				)
+			},
+			extract:        map[string]string{},
+			extractOptions: []ExtractOption{ExtractByGroup("g")},
+			wantErr:        true,
+			wantErrContain: "[Extract] duplicate key \"A\" of string[group=\"g\"]",
+		},
+		{
+			name: "error typ is not slice or map",
+			prepare: func(c *ContainerWrapper) error {
+				return c.Supply("a", dig.Group("g"))
+			},
+			extract:        "",
+			extractOptions: []ExtractOption{ExtractByGroup("g"), ExtractGroupOrdered()},
+			wantErr:        true,
+			wantErrContain: "[Extract] typ want slice or map[string]T",
+		},
+		{
+			name: "error constructor failed",
+			prepare: func(c *ContainerWrapper) error {
+				return c.Provide(func(i int) string { return "a" }, dig.Group("g"))
+			},
+			extract:        []string{},
+			extractOptions: []ExtractOption{ExtractByGroup("g"), ExtractGroupOrdered()},
+			wantErr:        true,
+			wantErrContain: "group_test.go",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+			if tt.scope {
+				c = c.Scope("child")
+			}
+			err := tt.prepare(c)
+			if (err != nil) != tt.wantPrepareErr {
+				t.Errorf("prepare() error = %v, wantErr %v", err, tt.wantPrepareErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			got, err := c.Extract(tt.extract, tt.extractOptions...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("c.Extract() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				if !strings.Contains(err.Error(), tt.wantErrContain) {
+					t.Errorf("c.Extract() error = %v, wantErrContain %s", err, tt.wantErrContain)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("c.Extract() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtract_groupOrdered(t *testing.T) {
+	c := dig.New()
+	_, err := Extract(c, []string{}, ExtractByGroup("g"), ExtractGroupOrdered())
+	if err == nil || !strings.Contains(err.Error(), "only support *digpro.ContainerWrapper") {
+		t.Errorf("Extract() error = %v, want only support *digpro.ContainerWrapper", err)
+	}
+}
@@ -1,8 +1,13 @@
 package internal
 
+import "reflect"
+
 type PropertyInfo struct {
 	ResolveCyclic bool
 	Inputs        []ProvideInput
 	Injected      bool
 	Error         error
+	// for ResolveCyclic of Provide, the origin constructor will be called when property inject
+	Constructor   reflect.Value
+	ConstructorPC uintptr
 }
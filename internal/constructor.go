@@ -0,0 +1,33 @@
+package internal
+
+import (
+	"reflect"
+)
+
+// WrapConstructor make a function which type is same as constructor,
+// after constructor return successfully (last return value is not a non-nil error), call after with all results.
+//
+// if constructor is not a function, return constructor itself and false
+func WrapConstructor(constructor interface{}, after func(results []reflect.Value)) (interface{}, bool) {
+	fv := reflect.ValueOf(constructor)
+	if constructor == nil || fv.Kind() != reflect.Func {
+		return constructor, false
+	}
+	ft := fv.Type()
+	wrapped := reflect.MakeFunc(ft, func(args []reflect.Value) []reflect.Value {
+		var results []reflect.Value
+		if ft.IsVariadic() {
+			results = fv.CallSlice(args)
+		} else {
+			results = fv.Call(args)
+		}
+		if len(results) != 0 {
+			if last := results[len(results)-1]; last.Type() == ErrorType && !last.IsNil() {
+				return results
+			}
+		}
+		after(results)
+		return results
+	})
+	return wrapped.Interface(), true
+}
@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestWrapConstructor(t *testing.T) {
+	tests := []struct {
+		name        string
+		constructor interface{}
+		args        []interface{}
+		wantOk      bool
+		wantCalled  bool
+		wantResults []interface{}
+	}{
+		{
+			name:        "not function",
+			constructor: 1,
+			wantOk:      false,
+		},
+		{
+			name:        "success",
+			constructor: func(a int) (int, error) { return a + 1, nil },
+			args:        []interface{}{1},
+			wantOk:      true,
+			wantCalled:  true,
+			wantResults: []interface{}{2, nil},
+		},
+		{
+			name:        "return error",
+			constructor: func(a int) (int, error) { return 0, errors.New("error") },
+			args:        []interface{}{1},
+			wantOk:      true,
+			wantCalled:  false,
+		},
+		{
+			name:        "variadic",
+			constructor: func(a ...int) int { return len(a) },
+			args:        []interface{}{[]int{1, 2}},
+			wantOk:      true,
+			wantCalled:  true,
+			wantResults: []interface{}{2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotResults []interface{}
+			called := false
+			wrapped, ok := WrapConstructor(tt.constructor, func(results []reflect.Value) {
+				called = true
+				for _, r := range results {
+					gotResults = append(gotResults, r.Interface())
+				}
+			})
+			if ok != tt.wantOk {
+				t.Errorf("WrapConstructor() ok = %v, want %v", ok, tt.wantOk)
+				return
+			}
+			if !ok {
+				return
+			}
+			args := make([]reflect.Value, 0, len(tt.args))
+			for _, arg := range tt.args {
+				args = append(args, reflect.ValueOf(arg))
+			}
+			fv := reflect.ValueOf(wrapped)
+			if fv.Type().IsVariadic() {
+				fv.CallSlice(args)
+			} else {
+				fv.Call(args)
+			}
+			if called != tt.wantCalled {
+				t.Errorf("after called = %v, want %v", called, tt.wantCalled)
+				return
+			}
+			if called && !reflect.DeepEqual(gotResults, tt.wantResults) {
+				t.Errorf("after results = %#v, want %#v", gotResults, tt.wantResults)
+			}
+		})
+	}
+}
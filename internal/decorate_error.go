@@ -24,6 +24,13 @@ func IsDigErrMissingDependencies(err error) bool {
 	return reflect.TypeOf(err).String() == "dig.errMissingDependencies"
 }
 
+func IsDigErrMissingTypes(err error) bool {
+	if err == nil {
+		return false
+	}
+	return reflect.TypeOf(err).String() == "dig.errMissingTypes"
+}
+
 func TryFixDigErrByFunc(err error, location *digcopy.Func) error {
 
 	if err == nil {
@@ -71,6 +78,14 @@ func WrapResolveCyclicError(err error, location *digcopy.Func, output *ProvideOu
 	}
 }
 
+// WrapErrorWithLocationFix like WrapErrorWithLocationForPC, but use fix.PC as location if it is not zero
+func WrapErrorWithLocationFix(fix LocationFixOption, f func(pc uintptr) error) error {
+	if fix.PC == 0 {
+		return WrapErrorWithLocationForPC(fix.CallSkip+1, f)
+	}
+	return TryFixDigErr(f(fix.PC), fix.PC)
+}
+
 func WrapErrorWithLocationForPC(callSkip int, f func(pc uintptr) error) error {
 	pc, _, _, ok := runtime.Caller(callSkip)
 	if !ok {
@@ -9,8 +9,10 @@ import (
 )
 
 type ExtractOptions struct {
-	Name  string
-	Group string
+	Name         string
+	Group        string
+	GroupOrdered bool
+	LocationFix  *LocationFixOption
 }
 
 type ExtractOption interface {
@@ -21,6 +23,14 @@ type ExtractOptionFunc func(*ExtractOptions)
 
 func (f ExtractOptionFunc) ApplyExtractOption(opts *ExtractOptions) { f(opts) }
 
+func ApplyExtractOptions(opts ...ExtractOption) ExtractOptions {
+	var options ExtractOptions
+	for _, o := range opts {
+		o.ApplyExtractOption(&options)
+	}
+	return options
+}
+
 func MakeExtractFunc(ptr interface{}, opts ...ExtractOption) interface{} {
 	if ptr == nil {
 		return fmt.Errorf("[MakeExtractFunc] can't extract an untyped nil")
@@ -37,10 +47,7 @@ func MakeExtractFunc(ptr interface{}, opts ...ExtractOption) interface{} {
 		return fmt.Errorf("[MakeExtractFunc] can't extract an error")
 	}
 
-	var options ExtractOptions
-	for _, o := range opts {
-		o.ApplyExtractOption(&options)
-	}
+	options := ApplyExtractOptions(opts...)
 	tags := []string{}
 	if options.Name != "" {
 		tags = append(tags, fmt.Sprintf(`name:"%s"`, options.Name))
@@ -74,25 +81,58 @@ func getPtrFinalKind(t reflect.Type) reflect.Kind {
 	return getPtrFinalKind(t.Elem())
 }
 
+// ExtractTypeOf return the type of value will be extracted by typ (not nil).
+// pointer of interface will do once addressing operation,
+// that means Extract(*interfaceA)) will return -> interfaceA
+func ExtractTypeOf(typ interface{}) reflect.Type {
+	t := reflect.TypeOf(typ)
+	if t.Kind() == reflect.Ptr && getPtrFinalKind(t) == reflect.Interface {
+		return t.Elem()
+	}
+	return t
+}
+
+// ExtractOptionalWithLocationForPC like ExtractWithLocationForPC, but return zero value and false if typ is not provided
+func ExtractOptionalWithLocationForPC(Invoke func(function interface{}, opts ...dig.InvokeOption) error, callSkip int, typ interface{}, opts ...ExtractOption) (interface{}, bool, error) {
+	if callSkip > 0 {
+		callSkip++
+	}
+	value, err := ExtractWithLocationForPC(Invoke, callSkip, typ, opts...)
+	if IsDigErrMissingDependencies(err) {
+		return reflect.Zero(ExtractTypeOf(typ)).Interface(), false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
 func ExtractWithLocationForPC(Invoke func(function interface{}, opts ...dig.InvokeOption) error, callSkip int, typ interface{}, opts ...ExtractOption) (interface{}, error) {
+	if callSkip > 0 {
+		callSkip++
+	}
+	return ExtractWithLocationFix(Invoke, LocationFixOption{CallSkip: callSkip}, typ, opts...)
+}
+
+// ExtractWithLocationFix like ExtractWithLocationForPC, but use fix.PC as location if it is not zero.
+// If opts has LocationFixOption, it will be used instead of fix
+func ExtractWithLocationFix(Invoke func(function interface{}, opts ...dig.InvokeOption) error, fix LocationFixOption, typ interface{}, opts ...ExtractOption) (interface{}, error) {
 	if typ == nil {
 		return nil, fmt.Errorf("can't extract an untyped nil")
 	}
-	typPtrInterface := reflect.New(reflect.TypeOf(typ))
-	// pointer of interface will do once addressing operation
-	// that means Extract(*interfaceA)) will return -> interfaceA
-	if reflect.TypeOf(typ).Kind() == reflect.Ptr && getPtrFinalKind(reflect.TypeOf(typ)) == reflect.Interface {
-		typPtrInterface = reflect.New(reflect.ValueOf(typ).Elem().Type())
-	}
+	typPtrInterface := reflect.New(ExtractTypeOf(typ))
 	f := MakeExtractFunc(typPtrInterface.Interface(), opts...)
 	if err, ok := f.(error); ok {
 		return nil, err
 	}
+	if options := ApplyExtractOptions(opts...); options.LocationFix != nil {
+		fix = *options.LocationFix
+	}
 	var err error
-	if callSkip <= 0 {
+	if fix.CallSkip <= 0 && fix.PC == 0 {
 		err = Invoke(f)
 	} else {
-		err = WrapErrorWithLocationForPC(callSkip, func(uintptr) error { return Invoke(f) })
+		err = WrapErrorWithLocationFix(fix, func(uintptr) error { return Invoke(f) })
 	}
 	if err != nil {
 		return nil, err
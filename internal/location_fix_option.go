@@ -6,4 +6,10 @@ type LocationFixOption struct {
 	dig.ProvideOption
 	dig.InvokeOption
 	CallSkip int
+	PC       uintptr // if not zero, use it as location instead of CallSkip
+}
+
+// ApplyExtractOption make LocationFixOption can be used as ExtractOption
+func (o LocationFixOption) ApplyExtractOption(opts *ExtractOptions) {
+	opts.LocationFix = &o
 }
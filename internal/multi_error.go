@@ -0,0 +1,42 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MultiError is a list of errors, it's Error() look like
+//   [0] error message 0
+//   [1] error message 1
+type MultiError []error
+
+func (errs MultiError) Error() string {
+	msgs := make([]string, 0, len(errs))
+	for i, err := range errs {
+		msgs = append(msgs, fmt.Sprintf("[%d] %s", i, err.Error()))
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// AppendError append non nil err to errs
+func AppendError(errs MultiError, err error) MultiError {
+	if err == nil {
+		return errs
+	}
+	if multiErr, ok := err.(MultiError); ok {
+		return append(errs, multiErr...)
+	}
+	return append(errs, err)
+}
+
+// ErrorOrNil return nil if errs is empty, return the only error if len(errs) == 1
+func (errs MultiError) ErrorOrNil() error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return errs
+	}
+}
@@ -0,0 +1,23 @@
+package internal
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMultiError(t *testing.T) {
+	var errs MultiError
+	if err := errs.ErrorOrNil(); err != nil {
+		t.Errorf("MultiError.ErrorOrNil() = %v, want nil", err)
+	}
+	errs = AppendError(errs, nil)
+	errs = AppendError(errs, errors.New("a"))
+	if err := errs.ErrorOrNil(); err == nil || err.Error() != "a" {
+		t.Errorf("MultiError.ErrorOrNil() = %v, want a", err)
+	}
+	errs = AppendError(errs, MultiError{errors.New("b"), errors.New("c")})
+	want := "[0] a\n[1] b\n[2] c"
+	if err := errs.ErrorOrNil(); err == nil || err.Error() != want {
+		t.Errorf("MultiError.ErrorOrNil() = %v, want %s", err, want)
+	}
+}
@@ -6,10 +6,21 @@ import (
 
 func ProvideWithLocationForPC(Provide func(constructor interface{}, opts ...dig.ProvideOption) error, callSkip int, constructor interface{}, opts ...dig.ProvideOption) error {
 	return WrapErrorWithLocationForPC(callSkip, func(pc uintptr) error {
-		if pc != 0 {
-			return Provide(constructor, append([]dig.ProvideOption{dig.LocationForPC(pc)}, opts...)...)
-		} else {
-			return Provide(constructor, opts...)
-		}
+		return provideWithPC(Provide, pc, constructor, opts...)
 	})
 }
+
+// ProvideWithLocationFix like ProvideWithLocationForPC, but use fix.PC as location if it is not zero
+func ProvideWithLocationFix(Provide func(constructor interface{}, opts ...dig.ProvideOption) error, fix LocationFixOption, constructor interface{}, opts ...dig.ProvideOption) error {
+	return WrapErrorWithLocationFix(fix, func(pc uintptr) error {
+		return provideWithPC(Provide, pc, constructor, opts...)
+	})
+}
+
+func provideWithPC(Provide func(constructor interface{}, opts ...dig.ProvideOption) error, pc uintptr, constructor interface{}, opts ...dig.ProvideOption) error {
+	if pc != 0 {
+		return Provide(constructor, append([]dig.ProvideOption{dig.LocationForPC(pc)}, opts...)...)
+	} else {
+		return Provide(constructor, opts...)
+	}
+}
@@ -6,15 +6,86 @@ import (
 	"strings"
 	"unsafe"
 
+	"github.com/rectcircle/digpro/internal/digcopy"
 	"go.uber.org/dig"
 )
 
 type ProvideInfosWrapper struct {
 	dig.ProvideInfo
+	Node            reflect.Value // *dig.node
+	Removed         bool          // has been removed from container, by override or remove
+	Kind            ProviderKind  // how the provider is registered
+	Overrides       []int         // indexes of the providers which are overridden by this provider
+	ResolveCyclic   bool          // is provided with digpro.ResolveCyclic option
 	exportedOutputs []ProvideOutput
 	exportedInputs  []ProvideInput
 }
 
+// ProviderKind is how the provider is registered
+type ProviderKind int
+
+const (
+	ProviderKindProvide ProviderKind = iota // Provide a constructor
+	ProviderKindStruct                      // Struct
+	ProviderKindSupply                      // Supply a value, include Config and SupplyEnv
+	ProviderKindBridge                      // the bridge of parent provider, see digpro.ContainerWrapper.Scope
+)
+
+// ProviderKindOption set the kind of the provider, only support *digpro.ContainerWrapper.Provide
+type ProviderKindOption struct {
+	dig.ProvideOption
+	Kind ProviderKind
+}
+
+func (k ProviderKind) String() string {
+	switch k {
+	case ProviderKindStruct:
+		return "Struct"
+	case ProviderKindSupply:
+		return "Supply"
+	case ProviderKindBridge:
+		return "Bridge"
+	default:
+		return "Provide"
+	}
+}
+
+// Called return true if the constructor of the node has been called
+func (piw *ProvideInfosWrapper) Called() bool {
+	if !piw.Node.IsValid() {
+		return false
+	}
+	return EnsureValueExported(piw.Node.Elem().FieldByName("called")).Interface().(bool)
+}
+
+// ConstructorType return the type of the constructor of the node
+func (piw *ProvideInfosWrapper) ConstructorType() reflect.Type {
+	if !piw.Node.IsValid() {
+		return nil
+	}
+	t, _ := EnsureValueExported(piw.Node.Elem().FieldByName("ctype")).Interface().(reflect.Type)
+	return t
+}
+
+// Location return location of the node
+func (piw *ProvideInfosWrapper) Location() *digcopy.Func {
+	if !piw.Node.IsValid() {
+		return nil
+	}
+	return NodeLocation(piw.Node)
+}
+
+// NodeLocation return location of node (*dig.node)
+func NodeLocation(node reflect.Value) *digcopy.Func {
+	originLocation := EnsureValueExported(node.Elem().FieldByName("location")).Elem()
+	location := &digcopy.Func{}
+	location.Name = originLocation.FieldByName("Name").Interface().(string)
+	location.Package = originLocation.FieldByName("Package").Interface().(string)
+	location.File = originLocation.FieldByName("File").Interface().(string)
+	location.Line = originLocation.FieldByName("Line").Interface().(int)
+	return location
+}
+
 func EnsureValueExported(value reflect.Value) reflect.Value {
 	if !value.CanSet() {
 		return reflect.NewAt(value.Type(), unsafe.Pointer(value.UnsafeAddr())).Elem()
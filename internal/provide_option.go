@@ -3,20 +3,26 @@ package internal
 import (
 	"reflect"
 
+	"github.com/rectcircle/digpro/internal/digcopy"
 	"go.uber.org/dig"
 )
 
 type ProvideOptions struct {
-	Name  string
-	Group string
-	Info  *dig.ProvideInfo
-	As    []interface{}
+	Name     string
+	Group    string
+	Info     *dig.ProvideInfo
+	As       []interface{}
+	Location *digcopy.Func
 }
 
 func ApplyProvideOptions(opts ...dig.ProvideOption) *ProvideOptions {
 	DigProvideOptionsPtrValue := reflect.New(DigProvideOptionsType)
 	for _, opt := range opts {
 		optValue := reflect.ValueOf(opt)
+		if optValue.Kind() != reflect.Func {
+			// not a dig option (e.g. digpro option)
+			continue
+		}
 		optValue.Call([]reflect.Value{DigProvideOptionsPtrValue})
 	}
 	DigProvideOptionValue := DigProvideOptionsPtrValue.Elem()
@@ -26,5 +32,14 @@ func ApplyProvideOptions(opts ...dig.ProvideOption) *ProvideOptions {
 		Info:  DigProvideOptionValue.FieldByName("Info").Interface().(*dig.ProvideInfo),
 		As:    DigProvideOptionValue.FieldByName("As").Interface().([]interface{}),
 	}
+	if location := DigProvideOptionValue.FieldByName("Location"); !location.IsNil() {
+		location = location.Elem()
+		provideOptions.Location = &digcopy.Func{
+			Name:    location.FieldByName("Name").Interface().(string),
+			Package: location.FieldByName("Package").Interface().(string),
+			File:    location.FieldByName("File").Interface().(string),
+			Line:    location.FieldByName("Line").Interface().(int),
+		}
+	}
 	return &provideOptions
 }
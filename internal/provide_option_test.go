@@ -4,6 +4,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/rectcircle/digpro/internal/digcopy"
 	"go.uber.org/dig"
 )
 
@@ -34,6 +35,29 @@ func TestApplyProvideOptions(t *testing.T) {
 				As:    []interface{}{i, i},
 			},
 		},
+		{
+			name: "location",
+			args: args{
+				opts: []dig.ProvideOption{
+					dig.LocationForPC(reflect.ValueOf(TestApplyProvideOptions).Pointer()),
+				},
+			},
+			want: &ProvideOptions{
+				Location: digcopy.InspectFunc(TestApplyProvideOptions),
+			},
+		},
+		{
+			name: "skip not dig option",
+			args: args{
+				opts: []dig.ProvideOption{
+					dig.Name("a"),
+					LocationFixOption{CallSkip: 1},
+				},
+			},
+			want: &ProvideOptions{
+				Name: "a",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
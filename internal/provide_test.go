@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"runtime"
 	"strings"
 	"testing"
 
@@ -51,3 +52,51 @@ func TestProvideWithLocationForPC(t *testing.T) {
 		})
 	}
 }
+
+func TestProvideWithLocationFix(t *testing.T) {
+	pc, _, _, _ := runtime.Caller(0)
+	type args struct {
+		c           *dig.Container
+		fix         LocationFixOption
+		constructor interface{}
+		opts        []dig.ProvideOption
+	}
+	tests := []struct {
+		name           string
+		args           args
+		wantErr        bool
+		wantErrContain string
+	}{
+		{
+			name: "error with right caller info by call skip",
+			args: args{
+				c:           dig.New(),
+				fix:         LocationFixOption{CallSkip: 2},
+				constructor: func() {},
+			},
+			wantErr:        true,
+			wantErrContain: tests.GetSelfSourceCodeFilePath(),
+		},
+		{
+			name: "error with right caller info by pc",
+			args: args{
+				c:           dig.New(),
+				fix:         LocationFixOption{PC: pc},
+				constructor: func() {},
+			},
+			wantErr:        true,
+			wantErrContain: "TestProvideWithLocationFix (" + tests.GetSelfSourceCodeFilePath(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ProvideWithLocationFix(tt.args.c.Provide, tt.args.fix, tt.args.constructor, tt.args.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ProvideWithLocationFix() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), tt.wantErrContain) {
+				t.Errorf("ProvideWithLocationFix() error want contain %s, got %s", tt.wantErrContain, err.Error())
+			}
+		})
+	}
+}
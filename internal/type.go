@@ -2,16 +2,21 @@ package internal
 
 import (
 	"reflect"
+	"time"
 
 	"go.uber.org/dig"
 )
 
 var DigInField = reflect.TypeOf(struct{ dig.In }{}).Field(0)
+var DigOutField = reflect.TypeOf(struct{ dig.Out }{}).Field(0)
+var TimeType = reflect.TypeOf(time.Time{})
 var ErrorType = reflect.TypeOf(new(error)).Elem()
 
 const (
-	DigGroupTag = "group"
-	DigNameTag  = "name"
+	DigGroupTag    = "group"
+	DigNameTag     = "name"
+	DigOptionalTag = "optional"
+	DefaultTag     = "default"
 )
 
 var DigProvideOptionsType reflect.Type // dig.provideOptions
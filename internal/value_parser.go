@@ -0,0 +1,88 @@
+package internal
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var DurationType = reflect.TypeOf(time.Duration(0))
+
+// IsParseValueSupported return true if typ is supported by ParseValue
+func IsParseValueSupported(typ reflect.Type) bool {
+	if typ == DurationType {
+		return true
+	}
+	switch typ.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return typ.Elem().Kind() != reflect.Slice && IsParseValueSupported(typ.Elem())
+	default:
+		return false
+	}
+}
+
+// ParseValue parse string to value of typ,
+// support bool, int*, uint*, float*, string, time.Duration and slice of these (comma-separated)
+func ParseValue(typ reflect.Type, s string) (reflect.Value, error) {
+	if !IsParseValueSupported(typ) {
+		return reflect.Value{}, fmt.Errorf("unsupported type %s", typ)
+	}
+	value := reflect.New(typ).Elem()
+	if typ == DurationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		value.SetInt(int64(d))
+		return value, nil
+	}
+	switch typ.Kind() {
+	case reflect.String:
+		value.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		value.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 0, typ.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		value.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(s, 0, typ.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		value.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, typ.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		value.SetFloat(f)
+	case reflect.Slice:
+		items := []string{}
+		if s != "" {
+			items = strings.Split(s, ",")
+		}
+		value = reflect.MakeSlice(typ, 0, len(items))
+		for _, item := range items {
+			itemValue, err := ParseValue(typ.Elem(), strings.TrimSpace(item))
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			value = reflect.Append(value, itemValue)
+		}
+	}
+	return value, nil
+}
@@ -0,0 +1,51 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type myString string
+
+func TestParseValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		typ     reflect.Type
+		s       string
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "string", typ: reflect.TypeOf(""), s: "a", want: "a"},
+		{name: "named string", typ: reflect.TypeOf(myString("")), s: "a", want: myString("a")},
+		{name: "bool", typ: reflect.TypeOf(false), s: "true", want: true},
+		{name: "int", typ: reflect.TypeOf(0), s: "-1", want: -1},
+		{name: "int8 overflow", typ: reflect.TypeOf(int8(0)), s: "128", wantErr: true},
+		{name: "uint16", typ: reflect.TypeOf(uint16(0)), s: "0x10", want: uint16(16)},
+		{name: "float32", typ: reflect.TypeOf(float32(0)), s: "1.5", want: float32(1.5)},
+		{name: "duration", typ: reflect.TypeOf(time.Duration(0)), s: "1m30s", want: 90 * time.Second},
+		{name: "int slice", typ: reflect.TypeOf([]int{}), s: "1, 2,3", want: []int{1, 2, 3}},
+		{name: "empty slice", typ: reflect.TypeOf([]string{}), s: "", want: []string{}},
+		{name: "duration slice", typ: reflect.TypeOf([]time.Duration{}), s: "1s,2s", want: []time.Duration{time.Second, 2 * time.Second}},
+		{name: "error bool", typ: reflect.TypeOf(false), s: "abc", wantErr: true},
+		{name: "error duration", typ: reflect.TypeOf(time.Duration(0)), s: "1", wantErr: true},
+		{name: "error slice item", typ: reflect.TypeOf([]int{}), s: "1,a", wantErr: true},
+		{name: "error nested slice", typ: reflect.TypeOf([][]int{}), s: "1", wantErr: true},
+		{name: "error struct", typ: reflect.TypeOf(struct{}{}), s: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseValue(tt.typ, tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseValue() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(got.Interface(), tt.want) {
+				t.Errorf("ParseValue() = %#v, want %#v", got.Interface(), tt.want)
+			}
+		})
+	}
+}
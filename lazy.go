@@ -0,0 +1,126 @@
+package digpro
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+
+	"github.com/rectcircle/digpro/internal"
+	"go.uber.org/dig"
+)
+
+// lazy is the non-generic implementation of digpro.Lazy
+type lazy struct {
+	mu      sync.Mutex
+	extract func() (interface{}, error)
+	done    bool
+	value   interface{}
+}
+
+// get call extract once successfully, and return the memoized value after that
+func (l *lazy) get() (interface{}, error) {
+	if l == nil {
+		return nil, errors.New("digpro.Lazy is not injected by *digpro.ContainerWrapper")
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.done {
+		return l.value, nil
+	}
+	value, err := l.extract()
+	if err != nil {
+		return nil, err
+	}
+	l.value, l.done = value, true
+	return value, nil
+}
+
+// lazyValue is implemented by *digpro.Lazy[T]
+type lazyValue interface {
+	setLazy(l *lazy)
+	elemType() reflect.Type
+}
+
+var lazyValueType = reflect.TypeOf(new(lazyValue)).Elem()
+
+func isLazyType(t reflect.Type) bool {
+	return t.Kind() != reflect.Ptr && reflect.PtrTo(t).Implements(lazyValueType)
+}
+
+// lazyElemOutput return the output of T, if the type of input is digpro.Lazy[T]
+func lazyElemOutput(input internal.ProvideInput) (output internal.ProvideOutput, ok bool) {
+	if input.Group != "" || !isLazyType(input.Type) {
+		return
+	}
+	return internal.ProvideOutput{Type: reflect.New(input.Type).Interface().(lazyValue).elemType(), Name: input.Name}, true
+}
+
+// lazyProvideMiddleware provide digpro.Lazy[T] for the inputs of provider (include the fields of digpro.ResolveCyclic Struct)
+func lazyProvideMiddleware(pc *provideContext) error {
+	err := pc.next()
+	if err != nil {
+		return err
+	}
+	pc.c.provideLazyInputs(pc.c.providerInputs(&pc.c.provideInfos[len(pc.c.provideInfos)-1]))
+	return nil
+}
+
+// provideLazyInputs provide digpro.Lazy[T] constructors for the inputs which type is digpro.Lazy[T] and not provided
+func (c *ContainerWrapper) provideLazyInputs(inputs []internal.ProvideInput) {
+	for _, input := range inputs {
+		if input.Group != "" || !isLazyType(input.Type) {
+			continue
+		}
+		output := internal.ProvideOutput{Type: input.Type, Name: input.Name}
+		if _, ok := c.lazyProvided[output]; ok {
+			continue
+		}
+		c.lazyProvided[output] = struct{}{}
+		opts := []dig.ProvideOption{}
+		if output.Name != "" {
+			opts = append(opts, dig.Name(output.Name))
+		}
+		ft := reflect.FuncOf(nil, []reflect.Type{output.Type}, false)
+		fv := reflect.MakeFunc(ft, func([]reflect.Value) []reflect.Value {
+			ptr := reflect.New(output.Type)
+			lv := ptr.Interface().(lazyValue)
+			elemType := lv.elemType()
+			lv.setLazy(&lazy{extract: func() (interface{}, error) {
+				elemPtr := reflect.New(elemType)
+				err := c.Invoke(internal.MakeExtractFunc(elemPtr.Interface(), ExtractByName(output.Name)))
+				return elemPtr.Elem().Interface(), err
+			}})
+			return []reflect.Value{ptr.Elem()}
+		})
+		// provide by dig.Container directly, it is not a user provider.
+		// if the user has provided it, the error will be ignored, user provider first
+		_ = c.Container.Provide(fv.Interface(), opts...)
+	}
+}
+
+// invokeInputs return the inputs of function, which is invoked by *digpro.ContainerWrapper.Invoke
+func invokeInputs(function interface{}) []internal.ProvideInput {
+	ft := reflect.TypeOf(function)
+	if ft == nil || ft.Kind() != reflect.Func {
+		return nil
+	}
+	inputs := []internal.ProvideInput{}
+	var walk func(t reflect.Type, name string)
+	walk = func(t reflect.Type, name string) {
+		if !dig.IsIn(t) {
+			inputs = append(inputs, internal.ProvideInput{Type: t, Name: name})
+			return
+		}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" || (f.Anonymous && f.Type == reflect.TypeOf(dig.In{})) || f.Tag.Get(internal.DigGroupTag) != "" {
+				continue
+			}
+			walk(f.Type, f.Tag.Get(internal.DigNameTag))
+		}
+	}
+	for i := 0; i < ft.NumIn(); i++ {
+		walk(ft.In(i), "")
+	}
+	return inputs
+}
@@ -0,0 +1,34 @@
+//go:build go1.18
+// +build go1.18
+
+package digpro_test
+
+import (
+	"fmt"
+
+	"github.com/rectcircle/digpro"
+)
+
+type LazyClient struct{}
+
+type LazyService struct {
+	Client digpro.Lazy[*LazyClient]
+}
+
+func ExampleLazy() {
+	c := digpro.New()
+	_ = c.Provide(func() *LazyClient { // please handle error in production
+		fmt.Println("new client")
+		return &LazyClient{}
+	})
+	_ = c.Struct(new(LazyService))
+	s, _ := c.Extract(new(LazyService))
+	fmt.Println("service created")
+	client, _ := s.(*LazyService).Client.Get()
+	_, _ = s.(*LazyService).Client.Get()
+	fmt.Println(client != nil)
+	// Output:
+	// service created
+	// new client
+	// true
+}
@@ -0,0 +1,62 @@
+//go:build go1.18
+// +build go1.18
+
+package digpro
+
+import "reflect"
+
+// Lazy defer the construction of T until Get is called first time, and the value of T is memoized.
+// Lazy[T] can be used as the field of Struct, the param of constructor and invoked function
+// (support name tag / dig.Name, but not support value group), only support *digpro.ContainerWrapper.
+//
+// It can be used to break startup ordering issues, and avoid building expensive values that only some code paths use.
+//
+// for example
+//   type Service struct {
+//   	Client digpro.Lazy[*Client]
+//   }
+//   c := digpro.New()
+//   _ = c.Provide(func() *Client { fmt.Println("new client"); return &Client{} }) // please handle error in production
+//   _ = c.Struct(new(Service))
+//   s, _ := c.Extract(new(Service))
+//   fmt.Println("service created")
+//   client, _ := s.(*Service).Client.Get()
+//   _, _ = s.(*Service).Client.Get()
+//   fmt.Println(client != nil)
+//   // Output:
+//   // service created
+//   // new client
+//   // true
+type Lazy[T any] struct {
+	l *lazy
+}
+
+// Get extract T from container when it is called first time, and return the memoized value after that.
+// If extract failed, the error will be returned, and will retry when Get is called next time.
+func (l Lazy[T]) Get() (T, error) {
+	value, err := l.l.get()
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	// value may be nil interface
+	result, _ := value.(T)
+	return result, nil
+}
+
+// MustGet like Get, but panic if has error
+func (l Lazy[T]) MustGet() T {
+	value, err := l.Get()
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+func (l *Lazy[T]) setLazy(_l *lazy) {
+	l.l = _l
+}
+
+func (l *Lazy[T]) elemType() reflect.Type {
+	return reflect.TypeOf(new(T)).Elem()
+}
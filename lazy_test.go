@@ -0,0 +1,242 @@
+//go:build go1.18
+// +build go1.18
+
+package digpro
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.uber.org/dig"
+)
+
+type lazyClient struct {
+	Value int
+}
+
+type lazyService struct {
+	Client  Lazy[*lazyClient]
+	Named   Lazy[string] `name:"named"`
+	Missing Lazy[int]    `optional:"true"`
+}
+
+type lazyCyclicA struct {
+	B Lazy[*lazyCyclicB]
+}
+
+type lazyCyclicB struct {
+	A *lazyCyclicA
+}
+
+func TestLazy(t *testing.T) {
+	called := 0
+	newClient := func() (*lazyClient, error) {
+		called++
+		if called == 1 {
+			return nil, errors.New("first call failed")
+		}
+		return &lazyClient{Value: called}, nil
+	}
+	c := New()
+	if err := firstError(
+		c.Provide(newClient),
+		c.Supply("a", dig.Name("named")),
+		c.Struct(new(lazyService)),
+	); err != nil {
+		t.Errorf("prepare error = %v", err)
+		return
+	}
+	s, err := c.Extract(new(lazyService))
+	if err != nil {
+		t.Errorf("c.Extract() error = %v", err)
+		return
+	}
+	service := s.(*lazyService)
+	if called != 0 {
+		t.Errorf("constructor called %d times before Get, want 0", called)
+	}
+	if _, err := service.Client.Get(); err == nil || !strings.Contains(err.Error(), "first call failed") {
+		t.Errorf("Lazy.Get() error = %v, want first call failed", err)
+	}
+	for i := 0; i < 2; i++ {
+		client, err := service.Client.Get()
+		if err != nil {
+			t.Errorf("Lazy.Get() error = %v", err)
+			return
+		}
+		if !reflect.DeepEqual(client, &lazyClient{Value: 2}) {
+			t.Errorf("Lazy.Get() = %#v, want %#v", client, &lazyClient{Value: 2})
+		}
+	}
+	if called != 2 {
+		t.Errorf("constructor called %d times, want 2", called)
+	}
+	if named := service.Named.MustGet(); named != "a" {
+		t.Errorf("Lazy.MustGet() = %s, want a", named)
+	}
+	if _, err := service.Missing.Get(); err == nil || !strings.Contains(err.Error(), "missing type: int") {
+		t.Errorf("Lazy.Get() error = %v, want missing type: int", err)
+	}
+}
+
+func TestLazy_provideAndInvoke(t *testing.T) {
+	c := New()
+	if err := firstError(
+		c.Supply(1),
+		c.Provide(func(i Lazy[int]) *lazyClient { return &lazyClient{Value: i.MustGet()} }),
+	); err != nil {
+		t.Errorf("prepare error = %v", err)
+		return
+	}
+	err := c.Invoke(func(in struct {
+		dig.In
+		Client Lazy[*lazyClient]
+	}) error {
+		client, err := in.Client.Get()
+		if err != nil {
+			return err
+		}
+		if client.Value != 1 {
+			return errors.New("client.Value want 1")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("c.Invoke() error = %v", err)
+	}
+}
+
+func TestLazy_cyclic(t *testing.T) {
+	c := New()
+	if err := firstError(
+		c.Struct(new(lazyCyclicA)),
+		c.Struct(new(lazyCyclicB)),
+	); err != nil {
+		t.Errorf("prepare error = %v", err)
+		return
+	}
+	a, err := c.Extract(new(lazyCyclicA))
+	if err != nil {
+		t.Errorf("c.Extract() error = %v", err)
+		return
+	}
+	b, err := a.(*lazyCyclicA).B.Get()
+	if err != nil {
+		t.Errorf("Lazy.Get() error = %v", err)
+		return
+	}
+	if b.A != a {
+		t.Errorf("b.A should be a")
+	}
+}
+
+func TestLazy_notInjected(t *testing.T) {
+	var l Lazy[int]
+	if _, err := l.Get(); err == nil {
+		t.Errorf("Lazy.Get() want error")
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Lazy.MustGet() want panic")
+		}
+	}()
+	l.MustGet()
+}
+
+func TestLazy_verify(t *testing.T) {
+	c := New()
+	if err := c.Struct(new(lazyService)); err != nil {
+		t.Errorf("prepare error = %v", err)
+		return
+	}
+	err := c.Verify()
+	if err == nil || !strings.Contains(err.Error(), "missing dependency digpro.Lazy[*github.com/rectcircle/digpro.lazyClient] of Struct at") ||
+		!strings.Contains(err.Error(), "missing dependency digpro.Lazy[string][name=\"named\"] of Struct at") ||
+		strings.Contains(err.Error(), "Lazy[int]") {
+		t.Errorf("c.Verify() error = %v", err)
+		return
+	}
+	if err := firstError(
+		c.Provide(func() *lazyClient { return &lazyClient{} }),
+		c.Supply("a", dig.Name("named")),
+	); err != nil {
+		t.Errorf("prepare error = %v", err)
+		return
+	}
+	if err := c.Verify(); err != nil {
+		t.Errorf("c.Verify() error = %v", err)
+	}
+}
+
+func TestLazy_remove(t *testing.T) {
+	c := New()
+	if err := firstError(
+		c.Struct(new(lazyService)),
+		c.Provide(func() *lazyClient { return &lazyClient{} }),
+		c.Supply("a", dig.Name("named")),
+		c.Supply(1),
+	); err != nil {
+		t.Errorf("prepare error = %v", err)
+		return
+	}
+	err := c.Remove(new(lazyClient))
+	if err == nil || !strings.Contains(err.Error(), "depends on digpro.Lazy[*github.com/rectcircle/digpro.lazyClient]") {
+		t.Errorf("c.Remove() error = %v, want depended by lazyService", err)
+	}
+	err = c.Remove("", ExtractByName("named"))
+	if err == nil || !strings.Contains(err.Error(), "depends on digpro.Lazy[string][name=\"named\"]") {
+		t.Errorf("c.Remove() error = %v, want depended by lazyService", err)
+	}
+	// Lazy[int] is optional input of lazyService
+	if err := c.Remove(int(0)); err != nil {
+		t.Errorf("c.Remove() error = %v", err)
+	}
+}
+
+func TestLazy_unusedProviders(t *testing.T) {
+	c := New()
+	if err := firstError(
+		c.Struct(new(lazyService)),
+		c.Provide(func() *lazyClient { return &lazyClient{} }),
+		c.Supply("a", dig.Name("named")),
+		c.Supply(1),
+		c.Supply(1.0),
+	); err != nil {
+		t.Errorf("prepare error = %v", err)
+		return
+	}
+	got := c.UnusedProviders(new(lazyService))
+	// Lazy[int] is optional input of lazyService, so only float64 is unused
+	if len(got) != 1 || !strings.HasPrefix(got[0], "Supply float64 at") {
+		t.Errorf("c.UnusedProviders() = %v, want [Supply float64 at ...]", got)
+	}
+}
+
+func TestLazy_exportGraph(t *testing.T) {
+	c := New()
+	if err := firstError(
+		c.Provide(func() *lazyClient { return &lazyClient{} }),
+		c.Struct(new(lazyService)),
+	); err != nil {
+		t.Errorf("prepare error = %v", err)
+		return
+	}
+	buf := bytes.Buffer{}
+	if err := c.ExportGraph(&buf, GraphFormatJSON); err != nil {
+		t.Errorf("c.ExportGraph() error = %v", err)
+		return
+	}
+	got := Graph{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Errorf("json.Unmarshal() error = %v", err)
+		return
+	}
+	want := []GraphEdge{{From: 1, To: 0, Input: GraphInput{Type: "digpro.Lazy[*github.com/rectcircle/digpro.lazyClient]"}, Lazy: true}}
+	if !reflect.DeepEqual(got.Edges, want) {
+		t.Errorf("c.ExportGraph() edges = %+v, want %+v", got.Edges, want)
+	}
+}
@@ -0,0 +1,387 @@
+package digpro
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rectcircle/digpro/internal"
+	"github.com/rectcircle/digpro/internal/digcopy"
+	"go.uber.org/dig"
+)
+
+// Hook is a pair of start and stop callbacks, either can be nil.
+type Hook struct {
+	OnStart func(context.Context) error
+	OnStop  func(context.Context) error
+}
+
+// Lifecycle allows constructors to register start and stop hooks.
+// *digpro.ContainerWrapper has provided a Lifecycle, so that constructor can depend on it.
+//
+// for example
+//   c := digpro.New()
+//   _ = c.Provide(func(lc digpro.Lifecycle) *Server {
+//   	s := &Server{}
+//   	lc.Append(digpro.Hook{
+//   		OnStart: s.Start,
+//   		OnStop:  s.Stop,
+//   	})
+//   	return s
+//   })
+type Lifecycle interface {
+	Append(hook Hook)
+}
+
+type lifecycleHook struct {
+	Hook
+	location *digcopy.Func
+}
+
+// rollbackTimeout is the timeout of calling OnStop hooks of the started hooks when OnStart hook failed, see Start
+var rollbackTimeout = 15 * time.Second
+
+type lifecycle struct {
+	mu      sync.Mutex // protect hooks
+	runMu   sync.Mutex // serialize start and stop, Append not require it, so that hooks can construct values which append hooks
+	hooks   []lifecycleHook
+	started int // hooks[:started] OnStart has been called successfully, protected by runMu
+}
+
+func newLifecycle() *lifecycle {
+	return &lifecycle{}
+}
+
+func (l *lifecycle) Append(hook Hook) {
+	var location *digcopy.Func
+	if pc, _, _, ok := runtime.Caller(1); ok {
+		location = digcopy.InspectFuncPC(pc)
+	}
+	l.append(hook, location)
+}
+
+func (l *lifecycle) append(hook Hook, location *digcopy.Func) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hooks = append(l.hooks, lifecycleHook{Hook: hook, location: location})
+}
+
+// hookAt return hooks[i], ok is false if i is out of range
+func (l *lifecycle) hookAt(i int) (hook lifecycleHook, ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if i >= len(l.hooks) {
+		return hook, false
+	}
+	return l.hooks[i], true
+}
+
+func (l *lifecycle) start(ctx context.Context) error {
+	l.runMu.Lock()
+	defer l.runMu.Unlock()
+	for {
+		// the hooks appended by running hooks will be started too
+		hook, ok := l.hookAt(l.started)
+		if !ok {
+			return nil
+		}
+		if hook.OnStart != nil {
+			if err := runHook(ctx, hook.OnStart); err != nil {
+				errs := internal.MultiError{fmt.Errorf("OnStart hook registered at %v failed: %w", hook.location, err)}
+				// rollback, stop all started hooks, ctx may be done, so the cancellation of ctx is ignored,
+				// and the rollback has its own timeout, so that a hanging OnStop hook will not block Start forever
+				rollbackCtx, cancel := context.WithTimeout(withoutCancel{ctx}, rollbackTimeout)
+				defer cancel()
+				return internal.AppendError(errs, l.stopStarted(rollbackCtx)).ErrorOrNil()
+			}
+		}
+		l.started++
+	}
+}
+
+func (l *lifecycle) stop(ctx context.Context) error {
+	l.runMu.Lock()
+	defer l.runMu.Unlock()
+	return l.stopStarted(ctx)
+}
+
+// stopStarted call OnStop of started hooks in reverse order, the hooks not be called (ctx is done) keep started
+func (l *lifecycle) stopStarted(ctx context.Context) error {
+	var errs internal.MultiError
+	for ; l.started > 0; l.started-- {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, fmt.Errorf("OnStop hooks of %d started hooks are not called: %w", l.started, err))
+			break
+		}
+		hook, _ := l.hookAt(l.started - 1)
+		if hook.OnStop == nil {
+			continue
+		}
+		if err := runHook(ctx, hook.OnStop); err != nil {
+			errs = append(errs, fmt.Errorf("OnStop hook registered at %v failed: %w", hook.location, err))
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+// withoutCancel is a context which is never canceled, but keep the values of parent context (like context.WithoutCancel since go1.21)
+type withoutCancel struct {
+	parent context.Context
+}
+
+func (withoutCancel) Deadline() (deadline time.Time, ok bool) { return }
+func (withoutCancel) Done() <-chan struct{}                   { return nil }
+func (withoutCancel) Err() error                              { return nil }
+func (c withoutCancel) Value(key interface{}) interface{}     { return c.parent.Value(key) }
+
+// runHook call f and return ctx.Err() if ctx done before f return
+func runHook(ctx context.Context, f func(context.Context) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- f(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type lifecycleHookProvideOption struct {
+	dig.ProvideOption
+	hook Hook
+}
+
+// LifecycleHook register a hook when the constructor is called,
+// support all high level api (*digpro.ContainerWrapper.Provide/Struct/Supply and digglobal).
+//
+// for example
+//   c := digpro.New()
+//   s := &Server{}
+//   _ = c.Supply(s, digpro.LifecycleHook(digpro.Hook{
+//   	OnStart: s.Start,
+//   	OnStop:  s.Stop,
+//   }))
+//   _, _ = c.Extract(new(Server)) // hook only be registered after the constructor is called
+//   _ = c.Start(context.Background())
+//   _ = c.Stop(context.Background())
+func LifecycleHook(hook Hook) dig.ProvideOption {
+	return lifecycleHookProvideOption{hook: hook}
+}
+
+type managedLifecycleProvideOption struct {
+	dig.ProvideOption
+}
+
+// ManagedLifecycle register the constructed value for lifecycle management automatically,
+// support all high level api (*digpro.ContainerWrapper.Provide/Struct/Supply and digglobal).
+//
+// The value should implement one or more of the following methods
+//   Start(context.Context) error // will be called by *digpro.ContainerWrapper.Start
+//   Stop(context.Context) error  // will be called by *digpro.ContainerWrapper.Stop
+//   Close() error                // io.Closer, will be called by *digpro.ContainerWrapper.Stop if Stop not exist
+//
+// for example
+//   c := digpro.New()
+//   _ = c.Struct(new(XxxDAO), digpro.ManagedLifecycle()) // *XxxDAO implements io.Closer
+//   _, _ = c.Extract(new(XxxDAO))
+//   _ = c.Start(context.Background())
+//   _ = c.Stop(context.Background()) // (*XxxDAO).Close() will be called
+func ManagedLifecycle() dig.ProvideOption {
+	return managedLifecycleProvideOption{}
+}
+
+type lifecycleStarter interface {
+	Start(context.Context) error
+}
+
+type lifecycleStopper interface {
+	Stop(context.Context) error
+}
+
+var (
+	lifecycleStarterType = reflect.TypeOf(new(lifecycleStarter)).Elem()
+	lifecycleStopperType = reflect.TypeOf(new(lifecycleStopper)).Elem()
+	ioCloserType         = reflect.TypeOf(new(io.Closer)).Elem()
+)
+
+func isManagedLifecycleType(t reflect.Type) bool {
+	return t.Kind() == reflect.Interface || t.Implements(lifecycleStarterType) || t.Implements(lifecycleStopperType) || t.Implements(ioCloserType)
+}
+
+// managedLifecycleHook make a hook by the methods of value
+func managedLifecycleHook(value reflect.Value) (hook Hook, ok bool) {
+	if !value.IsValid() || (value.Kind() == reflect.Interface || value.Kind() == reflect.Ptr) && value.IsNil() {
+		return
+	}
+	v := value.Interface()
+	if starter, is := v.(lifecycleStarter); is {
+		hook.OnStart = starter.Start
+		ok = true
+	}
+	if stopper, is := v.(lifecycleStopper); is {
+		hook.OnStop = stopper.Stop
+		ok = true
+	} else if closer, is := v.(io.Closer); is {
+		hook.OnStop = func(context.Context) error { return closer.Close() }
+		ok = true
+	}
+	return
+}
+
+// managedLifecycleValues flatten dig.Out results
+func managedLifecycleValues(value reflect.Value) []reflect.Value {
+	if !dig.IsOut(value.Type()) {
+		return []reflect.Value{value}
+	}
+	result := []reflect.Value{}
+	for i := 0; i < value.NumField(); i++ {
+		f := value.Type().Field(i)
+		if f.PkgPath != "" || (f.Anonymous && f.Type == reflect.TypeOf(dig.Out{})) {
+			continue
+		}
+		result = append(result, managedLifecycleValues(value.Field(i))...)
+	}
+	return result
+}
+
+// lifecycleProvideMiddleware wrap constructor to append hooks when constructor is called
+func lifecycleProvideMiddleware(pc *provideContext) error {
+	var (
+		hooks   []Hook
+		managed = false
+		_opts   = make([]dig.ProvideOption, 0, len(pc.opts))
+	)
+	for _, opt := range pc.opts {
+		if o, ok := opt.(lifecycleHookProvideOption); ok {
+			hooks = append(hooks, o.hook)
+		} else if _, ok := opt.(managedLifecycleProvideOption); ok {
+			managed = true
+		} else {
+			_opts = append(_opts, opt)
+		}
+	}
+	pc.opts = _opts
+	if len(hooks) == 0 && !managed {
+		return pc.next()
+	}
+	if managed {
+		if _, digproOpts := filterProvideOptionAndGetDigproOptions(pc.opts); digproOpts.enableResolveCyclic {
+			// the result of constructor will be copied into the placeholder and discarded, see digpro.ResolveCyclic
+			return errors.New("cannot use digpro.ManagedLifecycle() with digpro.ResolveCyclic() in Provide, please use Struct or register hooks by digpro.LifecycleHook()")
+		}
+		if err := checkManagedLifecycleConstructor(pc.constructor); err != nil {
+			return err
+		}
+	}
+	return pc.wrapConstructor(func(results []reflect.Value, location *digcopy.Func) {
+		for _, hook := range hooks {
+			pc.c.lifecycle.append(hook, location)
+		}
+		if !managed {
+			return
+		}
+		for _, result := range results {
+			if result.Type() == internal.ErrorType {
+				continue
+			}
+			for _, value := range managedLifecycleValues(result) {
+				if hook, ok := managedLifecycleHook(value); ok {
+					pc.c.lifecycle.append(hook, location)
+				}
+			}
+		}
+	})
+}
+
+// checkManagedLifecycleConstructor check the results of constructor has any value can be managed
+func checkManagedLifecycleConstructor(constructor interface{}) error {
+	ft := reflect.TypeOf(constructor)
+	if ft == nil || ft.Kind() != reflect.Func {
+		// let dig report error
+		return nil
+	}
+	resultTypes := []reflect.Type{}
+	resultTypeNames := []string{}
+	for i := 0; i < ft.NumOut(); i++ {
+		if ft.Out(i) != internal.ErrorType {
+			resultTypes = append(resultTypes, ft.Out(i))
+			resultTypeNames = append(resultTypeNames, ft.Out(i).String())
+		}
+	}
+	for len(resultTypes) != 0 {
+		t := resultTypes[0]
+		resultTypes = resultTypes[1:]
+		if dig.IsOut(t) {
+			for i := 0; i < t.NumField(); i++ {
+				if f := t.Field(i); f.PkgPath == "" && !(f.Anonymous && f.Type == reflect.TypeOf(dig.Out{})) {
+					resultTypes = append(resultTypes, f.Type)
+				}
+			}
+			continue
+		}
+		if isManagedLifecycleType(t) {
+			return nil
+		}
+	}
+	return fmt.Errorf("digpro.ManagedLifecycle() require the value implements Start(context.Context) error, Stop(context.Context) error or io.Closer, but got %s", strings.Join(resultTypeNames, ", "))
+}
+
+// wrapConstructor wrap pc.constructor to call after when it is called successfully,
+// the location of constructor is kept and pass to after
+func (pc *provideContext) wrapConstructor(after func(results []reflect.Value, location *digcopy.Func)) error {
+	location := internal.ApplyProvideOptions(pc.opts...).Location
+	constructor, ok := internal.WrapConstructor(pc.constructor, func(results []reflect.Value) {
+		after(results, location)
+	})
+	if !ok {
+		// let dig report error
+		return pc.next()
+	}
+	if location == nil {
+		fptr := reflect.ValueOf(pc.constructor).Pointer()
+		location = digcopy.InspectFuncPC(fptr)
+		pc.opts = append([]dig.ProvideOption{dig.LocationForPC(fptr)}, pc.opts...)
+	}
+	pc.constructor = constructor
+	return pc.next()
+}
+
+// Start call all OnStart hooks in the order they were registered,
+// that is dependency order, because a hook is registered when it's constructor is called.
+// Note: only the hooks of the called constructors will be run, so please call Start after Invoke / Extract.
+//
+// If a OnStart hook failed, the OnStop hooks of the started hooks will be called in reverse order
+// (even if ctx is done, with a timeout of 15 seconds), and all errors will be returned.
+// The hooks whose OnStop has not been called in the timeout keep started, and can be stopped by calling Stop.
+//
+// Start will be return ctx.Err() when ctx is done, use context.WithTimeout to set a timeout.
+//
+// for example
+//   c := digpro.New()
+//   _ = c.Provide(func(lc digpro.Lifecycle) *Server { ... })
+//   s, _ := c.Extract(new(Server))
+//   ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+//   defer cancel()
+//   _ = c.Start(ctx)
+func (c *ContainerWrapper) Start(ctx context.Context) error {
+	return c.lifecycle.start(ctx)
+}
+
+// Stop call OnStop hooks of all started hooks in reverse order, and return all errors.
+//
+// Stop will be return ctx.Err() when ctx is done, use context.WithTimeout to set a timeout,
+// the hooks whose OnStop has not been called keep started, and can be stopped by calling Stop again.
+func (c *ContainerWrapper) Stop(ctx context.Context) error {
+	return c.lifecycle.stop(ctx)
+}
@@ -0,0 +1,77 @@
+package digpro_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rectcircle/digpro"
+)
+
+type DB struct{}
+
+func (db *DB) Close() error {
+	fmt.Println("db closed")
+	return nil
+}
+
+type Server struct {
+	DB *DB
+}
+
+func (s *Server) Start(ctx context.Context) error {
+	fmt.Println("server started")
+	return nil
+}
+
+func (s *Server) Stop(ctx context.Context) error {
+	fmt.Println("server stopped")
+	return nil
+}
+
+func ExampleLifecycle() {
+	c := digpro.New()
+	_ = c.Provide(func(lc digpro.Lifecycle) *DB { // please handle error in production
+		db := &DB{}
+		lc.Append(digpro.Hook{
+			OnStart: func(context.Context) error {
+				fmt.Println("db opened")
+				return nil
+			},
+			OnStop: func(context.Context) error {
+				return db.Close()
+			},
+		})
+		return db
+	})
+	s := &Server{}
+	_ = c.Struct(s, digpro.LifecycleHook(digpro.Hook{
+		OnStart: s.Start,
+		OnStop:  s.Stop,
+	}))
+	_, _ = c.Extract(new(Server))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	_ = c.Start(ctx)
+	_ = c.Stop(ctx)
+	// Output:
+	// db opened
+	// server started
+	// server stopped
+	// db closed
+}
+
+func ExampleManagedLifecycle() {
+	c := digpro.New()
+	_ = c.Struct(new(DB), digpro.ManagedLifecycle()) // please handle error in production
+	_ = c.Struct(new(Server), digpro.ManagedLifecycle())
+	_, _ = c.Extract(new(Server))
+
+	_ = c.Start(context.Background())
+	_ = c.Stop(context.Background())
+	// Output:
+	// server started
+	// server stopped
+	// db closed
+}
@@ -0,0 +1,438 @@
+package digpro
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/dig"
+)
+
+type lifecycleRecorder struct {
+	records []string
+}
+
+func (r *lifecycleRecorder) hook(name string, startErr, stopErr error) Hook {
+	return Hook{
+		OnStart: func(context.Context) error {
+			r.records = append(r.records, "start "+name)
+			return startErr
+		},
+		OnStop: func(context.Context) error {
+			r.records = append(r.records, "stop "+name)
+			return stopErr
+		},
+	}
+}
+
+type lifecycleA struct {
+	B *lifecycleB
+}
+
+type lifecycleB struct {
+	Value int
+}
+
+func TestContainerWrapper_Lifecycle(t *testing.T) {
+	type args struct {
+		prepare func(c *ContainerWrapper, r *lifecycleRecorder) error
+	}
+	tests := []struct {
+		name                string
+		args                args
+		wantStartErr        bool
+		wantStartErrContain string
+		wantStopErr         bool
+		wantStopErrContain  string
+		want                []string
+	}{
+		{
+			name: "dependency order",
+			args: args{
+				prepare: func(c *ContainerWrapper, r *lifecycleRecorder) error {
+					return firstError(
+						c.Supply(1, LifecycleHook(r.hook("int", nil, nil))),
+						c.Provide(func(lc Lifecycle, i int) *lifecycleB {
+							lc.Append(r.hook("*lifecycleB", nil, nil))
+							return &lifecycleB{Value: i}
+						}),
+						c.Struct(new(lifecycleA), LifecycleHook(r.hook("*lifecycleA", nil, nil))),
+						c.Supply("unused", LifecycleHook(r.hook("string", nil, nil))),
+					)
+				},
+			},
+			want: []string{
+				"start int", "start *lifecycleB", "start *lifecycleA",
+				"stop *lifecycleA", "stop *lifecycleB", "stop int",
+			},
+		},
+		{
+			name: "start error rollback",
+			args: args{
+				prepare: func(c *ContainerWrapper, r *lifecycleRecorder) error {
+					return firstError(
+						c.Supply(1, LifecycleHook(r.hook("int", nil, errors.New("stop int error")))),
+						c.Provide(func(lc Lifecycle, i int) *lifecycleB {
+							lc.Append(r.hook("*lifecycleB", errors.New("start *lifecycleB error"), nil))
+							return &lifecycleB{Value: i}
+						}),
+						c.Struct(new(lifecycleA), LifecycleHook(r.hook("*lifecycleA", nil, nil))),
+					)
+				},
+			},
+			wantStartErr:        true,
+			wantStartErrContain: "start *lifecycleB error",
+			want: []string{
+				"start int", "start *lifecycleB", "stop int",
+			},
+		},
+		{
+			name: "stop error",
+			args: args{
+				prepare: func(c *ContainerWrapper, r *lifecycleRecorder) error {
+					return firstError(
+						c.Supply(1, LifecycleHook(r.hook("int", nil, errors.New("stop int error")))),
+						c.Provide(func(lc Lifecycle, i int) *lifecycleB {
+							lc.Append(r.hook("*lifecycleB", nil, errors.New("stop *lifecycleB error")))
+							return &lifecycleB{Value: i}
+						}),
+						c.Struct(new(lifecycleA)),
+					)
+				},
+			},
+			wantStopErr:        true,
+			wantStopErrContain: "[1] OnStop hook registered at",
+			want: []string{
+				"start int", "start *lifecycleB",
+				"stop *lifecycleB", "stop int",
+			},
+		},
+		{
+			name: "start timeout",
+			args: args{
+				prepare: func(c *ContainerWrapper, r *lifecycleRecorder) error {
+					return firstError(
+						c.Supply(1),
+						c.Provide(func(lc Lifecycle, i int) *lifecycleB {
+							lc.Append(Hook{OnStart: func(ctx context.Context) error {
+								<-ctx.Done()
+								time.Sleep(10 * time.Millisecond)
+								return nil
+							}})
+							return &lifecycleB{Value: i}
+						}),
+						c.Struct(new(lifecycleA)),
+					)
+				},
+			},
+			wantStartErr:        true,
+			wantStartErrContain: context.DeadlineExceeded.Error(),
+			want:                nil,
+		},
+		{
+			name: "start timeout rollback",
+			args: args{
+				prepare: func(c *ContainerWrapper, r *lifecycleRecorder) error {
+					return firstError(
+						c.Supply(1, LifecycleHook(r.hook("int", nil, nil))),
+						c.Provide(func(lc Lifecycle, i int) *lifecycleB {
+							lc.Append(Hook{OnStart: func(ctx context.Context) error {
+								<-ctx.Done()
+								return ctx.Err()
+							}})
+							return &lifecycleB{Value: i}
+						}),
+						c.Struct(new(lifecycleA)),
+					)
+				},
+			},
+			wantStartErr:        true,
+			wantStartErrContain: context.DeadlineExceeded.Error(),
+			want:                []string{"start int", "stop int"},
+		},
+		{
+			name: "append in OnStart",
+			args: args{
+				prepare: func(c *ContainerWrapper, r *lifecycleRecorder) error {
+					return firstError(
+						c.Provide(func(lc Lifecycle) int {
+							lc.Append(r.hook("int", nil, nil))
+							return 1
+						}),
+						c.Provide(func(lc Lifecycle) *lifecycleB {
+							lc.Append(Hook{OnStart: func(ctx context.Context) error {
+								// the constructor of int append a hook
+								_, err := c.Extract(0)
+								return err
+							}})
+							return &lifecycleB{}
+						}),
+						c.Struct(new(lifecycleA)),
+					)
+				},
+			},
+			want: []string{"start int", "stop int"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+			r := &lifecycleRecorder{}
+			if err := tt.args.prepare(c, r); err != nil {
+				t.Errorf("prepare() error = %v", err)
+				return
+			}
+			if _, err := c.Extract(new(lifecycleA)); err != nil {
+				t.Errorf("c.Extract() error = %v", err)
+				return
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			err := c.Start(ctx)
+			if (err != nil) != tt.wantStartErr {
+				t.Errorf("c.Start() error = %v, wantErr %v", err, tt.wantStartErr)
+				return
+			}
+			if err != nil && !strings.Contains(err.Error(), tt.wantStartErrContain) {
+				t.Errorf("c.Start() error = %v, wantErrContain %s", err, tt.wantStartErrContain)
+				return
+			}
+			if err == nil {
+				err = c.Stop(ctx)
+				if (err != nil) != tt.wantStopErr {
+					t.Errorf("c.Stop() error = %v, wantErr %v", err, tt.wantStopErr)
+					return
+				}
+				if err != nil && !strings.Contains(err.Error(), tt.wantStopErrContain) {
+					t.Errorf("c.Stop() error = %v, wantErrContain %s", err, tt.wantStopErrContain)
+					return
+				}
+			}
+			if !reflect.DeepEqual(r.records, tt.want) {
+				t.Errorf("records = %#v, want %#v", r.records, tt.want)
+			}
+		})
+	}
+}
+
+func TestContainerWrapper_Stop_retry(t *testing.T) {
+	c := New()
+	r := &lifecycleRecorder{}
+	if err := c.Supply(1, LifecycleHook(r.hook("int", nil, nil))); err != nil {
+		t.Errorf("prepare() error = %v", err)
+		return
+	}
+	if _, err := c.Extract(0); err != nil {
+		t.Errorf("c.Extract() error = %v", err)
+		return
+	}
+	if err := c.Start(context.Background()); err != nil {
+		t.Errorf("c.Start() error = %v", err)
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := c.Stop(ctx); err == nil || !strings.Contains(err.Error(), context.Canceled.Error()) {
+		t.Errorf("c.Stop() error = %v, want %v", err, context.Canceled)
+	}
+	if err := c.Stop(context.Background()); err != nil {
+		t.Errorf("c.Stop() error = %v", err)
+	}
+	want := []string{"start int", "stop int"}
+	if !reflect.DeepEqual(r.records, want) {
+		t.Errorf("records = %#v, want %#v", r.records, want)
+	}
+}
+
+func TestContainerWrapper_Start_rollbackTimeout(t *testing.T) {
+	defer func(timeout time.Duration) { rollbackTimeout = timeout }(rollbackTimeout)
+	rollbackTimeout = 50 * time.Millisecond
+	hang := make(chan struct{})
+	defer close(hang)
+	c := New()
+	r := &lifecycleRecorder{}
+	if err := firstError(
+		c.Supply(1, LifecycleHook(r.hook("int", nil, nil))),
+		c.Supply(true, LifecycleHook(Hook{OnStop: func(context.Context) error {
+			// hang and ignore ctx
+			<-hang
+			return nil
+		}})),
+		c.Supply("a", LifecycleHook(r.hook("string", errors.New("start string error"), nil))),
+	); err != nil {
+		t.Errorf("prepare() error = %v", err)
+		return
+	}
+	if err := c.Invoke(func(int, bool, string) {}); err != nil {
+		t.Errorf("c.Invoke() error = %v", err)
+		return
+	}
+	err := c.Start(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "start string error") || !strings.Contains(err.Error(), context.DeadlineExceeded.Error()) {
+		t.Errorf("c.Start() error = %v, want start error and rollback timeout", err)
+	}
+	// the hook not stopped in rollback can be stopped by Stop
+	if err := c.Stop(context.Background()); err != nil {
+		t.Errorf("c.Stop() error = %v", err)
+	}
+	want := []string{"start int", "start string", "stop int"}
+	if !reflect.DeepEqual(r.records, want) {
+		t.Errorf("records = %#v, want %#v", r.records, want)
+	}
+}
+
+func firstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type managedStarterStopper struct {
+	r *lifecycleRecorder
+}
+
+func (m *managedStarterStopper) Start(context.Context) error {
+	m.r.records = append(m.r.records, "start *managedStarterStopper")
+	return nil
+}
+
+func (m *managedStarterStopper) Stop(context.Context) error {
+	m.r.records = append(m.r.records, "stop *managedStarterStopper")
+	return nil
+}
+
+func (m *managedStarterStopper) Close() error {
+	m.r.records = append(m.r.records, "close *managedStarterStopper")
+	return nil
+}
+
+type managedCloser struct {
+	S *managedStarterStopper
+	r *lifecycleRecorder `digpro:"ignore"`
+}
+
+func (m *managedCloser) Close() error {
+	m.r.records = append(m.r.records, "close *managedCloser")
+	return nil
+}
+
+func TestManagedLifecycle(t *testing.T) {
+	type args struct {
+		prepare func(c *ContainerWrapper, r *lifecycleRecorder) error
+	}
+	tests := []struct {
+		name           string
+		args           args
+		wantErr        bool
+		wantErrContain string
+		want           []string
+	}{
+		{
+			name: "error not implements",
+			args: args{
+				prepare: func(c *ContainerWrapper, r *lifecycleRecorder) error {
+					return c.Struct(new(lifecycleB), ManagedLifecycle())
+				},
+			},
+			wantErr:        true,
+			wantErrContain: "but got *digpro.lifecycleB",
+		},
+		{
+			name: "error provide with resolve cyclic",
+			args: args{
+				prepare: func(c *ContainerWrapper, r *lifecycleRecorder) error {
+					return c.Provide(func() *managedCloser { return &managedCloser{r: r} }, ManagedLifecycle(), ResolveCyclic())
+				},
+			},
+			wantErr:        true,
+			wantErrContain: "cannot use digpro.ManagedLifecycle() with digpro.ResolveCyclic() in Provide",
+		},
+		{
+			name: "struct with resolve cyclic",
+			args: args{
+				prepare: func(c *ContainerWrapper, r *lifecycleRecorder) error {
+					return firstError(
+						c.Supply(&managedStarterStopper{r: r}),
+						c.Struct(&managedCloser{r: r}, ManagedLifecycle(), ResolveCyclic()),
+					)
+				},
+			},
+			want: []string{"close *managedCloser"},
+		},
+		{
+			name: "struct",
+			args: args{
+				prepare: func(c *ContainerWrapper, r *lifecycleRecorder) error {
+					return firstError(
+						c.Supply(&managedStarterStopper{r: r}, ManagedLifecycle()),
+						c.Struct(&managedCloser{r: r}, ManagedLifecycle()),
+					)
+				},
+			},
+			want: []string{
+				"start *managedStarterStopper",
+				"close *managedCloser", "stop *managedStarterStopper",
+			},
+		},
+		{
+			name: "provide result object",
+			args: args{
+				prepare: func(c *ContainerWrapper, r *lifecycleRecorder) error {
+					type out struct {
+						dig.Out
+						S *managedStarterStopper
+						I int
+					}
+					return firstError(
+						c.Provide(func() (out, error) {
+							return out{S: &managedStarterStopper{r: r}}, nil
+						}, ManagedLifecycle()),
+						c.Struct(&managedCloser{r: r}),
+					)
+				},
+			},
+			want: []string{
+				"start *managedStarterStopper",
+				"stop *managedStarterStopper",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+			r := &lifecycleRecorder{}
+			err := tt.args.prepare(c, r)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("prepare() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				if !strings.Contains(err.Error(), tt.wantErrContain) {
+					t.Errorf("prepare() error = %v, wantErrContain %s", err, tt.wantErrContain)
+				}
+				return
+			}
+			if _, err := c.Extract(new(managedCloser)); err != nil {
+				t.Errorf("c.Extract() error = %v", err)
+				return
+			}
+			if err := c.Start(context.Background()); err != nil {
+				t.Errorf("c.Start() error = %v", err)
+				return
+			}
+			if err := c.Stop(context.Background()); err != nil {
+				t.Errorf("c.Stop() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(r.records, tt.want) {
+				t.Errorf("records = %#v, want %#v", r.records, tt.want)
+			}
+		})
+	}
+}
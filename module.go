@@ -0,0 +1,128 @@
+package digpro
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/rectcircle/digpro/internal"
+	"go.uber.org/dig"
+)
+
+// ModuleOption is a part of module, made by Module, ModuleProvide, ModuleStruct, ModuleSupply and ModuleInvoke
+type ModuleOption interface {
+	applyModule(c *ContainerWrapper, path string) error
+}
+
+type module struct {
+	name    string
+	options []ModuleOption
+}
+
+func (m *module) applyModule(c *ContainerWrapper, path string) error {
+	path = joinModulePath(path, m.name)
+	for _, option := range m.options {
+		if err := option.applyModule(c, path); err != nil {
+			if _, ok := option.(*module); ok {
+				// has been prefixed by sub module
+				return err
+			}
+			return fmt.Errorf("[Module %s] %w", path, err)
+		}
+	}
+	return nil
+}
+
+func joinModulePath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "/" + name
+}
+
+type moduleOptionFunc func(c *ContainerWrapper) error
+
+func (f moduleOptionFunc) applyModule(c *ContainerWrapper, _ string) error {
+	return f(c)
+}
+
+// callerPC return the pc of the caller of ModuleXxx function
+func callerPC() uintptr {
+	pc, _, _, _ := runtime.Caller(2)
+	return pc
+}
+
+// Module bundles providers and invokes into a module, and the module can be nested.
+// Use *digpro.ContainerWrapper.Apply to apply the module, the options will be applied in order.
+// If an option failed, the error will be prefixed with the module path (e.g. [Module app/db]).
+//
+// for example
+//   type DB struct {
+//   	DSN string `name:"dsn"`
+//   }
+//   var DBModule = digpro.Module("db",
+//   	digpro.ModuleSupply("this is db dsn", dig.Name("dsn")),
+//   	digpro.ModuleStruct(new(DB)),
+//   )
+//   var AppModule = digpro.Module("app",
+//   	DBModule,
+//   	digpro.ModuleInvoke(func(db *DB) { fmt.Println(db.DSN) }),
+//   )
+//   c := digpro.New()
+//   _ = c.Apply(AppModule) // please handle error in production
+//   // Output: this is db dsn
+func Module(name string, options ...ModuleOption) ModuleOption {
+	return &module{name: name, options: options}
+}
+
+// ModuleProvide make a module option, which call *digpro.ContainerWrapper.Provide when applied.
+// Support all options of *digpro.ContainerWrapper.Provide (e.g. digpro.Override()).
+func ModuleProvide(constructor interface{}, opts ...dig.ProvideOption) ModuleOption {
+	pc := callerPC()
+	return moduleOptionFunc(func(c *ContainerWrapper) error {
+		_opts, _ := filterProvideOptionAndGetDigproOptions(opts, locationFixOptionType)
+		return internal.ProvideWithLocationFix(c.Provide, internal.LocationFixOption{PC: pc}, constructor, _opts...)
+	})
+}
+
+// ModuleStruct make a module option, which call *digpro.ContainerWrapper.Struct when applied.
+// Support all options of *digpro.ContainerWrapper.Struct (e.g. digpro.Override(), digpro.ResolveCyclic()).
+func ModuleStruct(structOrStructPtr interface{}, opts ...dig.ProvideOption) ModuleOption {
+	pc := callerPC()
+	return moduleOptionFunc(func(c *ContainerWrapper) error {
+		return c.Struct(structOrStructPtr, append([]dig.ProvideOption{internal.LocationFixOption{PC: pc}}, opts...)...)
+	})
+}
+
+// ModuleSupply make a module option, which call *digpro.ContainerWrapper.Supply when applied.
+// Support all options of *digpro.ContainerWrapper.Supply (e.g. digpro.Override()).
+func ModuleSupply(value interface{}, opts ...dig.ProvideOption) ModuleOption {
+	pc := callerPC()
+	return moduleOptionFunc(func(c *ContainerWrapper) error {
+		return c.Supply(value, append([]dig.ProvideOption{internal.LocationFixOption{PC: pc}}, opts...)...)
+	})
+}
+
+// ModuleInvoke make a module option, which call *digpro.ContainerWrapper.Invoke when applied.
+func ModuleInvoke(function interface{}, opts ...dig.InvokeOption) ModuleOption {
+	pc := callerPC()
+	return moduleOptionFunc(func(c *ContainerWrapper) error {
+		return c.Invoke(function, append([]dig.InvokeOption{internal.LocationFixOption{PC: pc}}, opts...)...)
+	})
+}
+
+// Apply the modules in order, stop and return the error when a module failed.
+//
+// for example
+//   c := digpro.New()
+//   err := c.Apply(
+//   	digpro.Module("db", ...),
+//   	digpro.Module("http", ...),
+//   )
+func (c *ContainerWrapper) Apply(modules ...ModuleOption) error {
+	for _, m := range modules {
+		if err := m.applyModule(c, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
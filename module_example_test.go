@@ -0,0 +1,35 @@
+package digpro_test
+
+import (
+	"fmt"
+
+	"github.com/rectcircle/digpro"
+	"go.uber.org/dig"
+)
+
+func ExampleModule() {
+	type DB struct {
+		DSN string `name:"dsn"`
+	}
+	var DBModule = digpro.Module("db",
+		digpro.ModuleSupply("this is db dsn", dig.Name("dsn")),
+		digpro.ModuleStruct(new(DB)),
+	)
+	var AppModule = digpro.Module("app",
+		DBModule,
+		digpro.ModuleInvoke(func(db *DB) { fmt.Println(db.DSN) }),
+	)
+	c := digpro.New()
+	_ = c.Apply(AppModule) // please handle error in production
+	// Output: this is db dsn
+}
+
+func ExampleContainerWrapper_Apply() {
+	c := digpro.New()
+	err := c.Apply(
+		digpro.Module("db", digpro.ModuleSupply(1)),
+		digpro.Module("test", digpro.ModuleSupply(2)),
+	)
+	fmt.Println(err != nil)
+	// Output: true
+}
@@ -0,0 +1,109 @@
+package digpro
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestContainerWrapper_Apply(t *testing.T) {
+	var invoked []string
+	tests := []struct {
+		name           string
+		modules        []ModuleOption
+		wantErr        bool
+		wantErrContain string
+		extract        interface{}
+		want           interface{}
+		wantInvoked    []string
+	}{
+		{
+			name: "provide struct supply and invoke",
+			modules: []ModuleOption{
+				Module("app",
+					ModuleSupply(1),
+					ModuleProvide(func() string { return "a" }),
+					ModuleStruct(new(lifecycleB)),
+					ModuleInvoke(func(b *lifecycleB) { invoked = append(invoked, "app") }),
+				),
+			},
+			extract:     new(lifecycleB),
+			want:        &lifecycleB{Value: 1},
+			wantInvoked: []string{"app"},
+		},
+		{
+			name: "nested and override",
+			modules: []ModuleOption{
+				Module("app",
+					Module("db",
+						ModuleSupply(1),
+						ModuleStruct(new(lifecycleB)),
+					),
+					ModuleSupply(2, Override()),
+				),
+				Module("test",
+					ModuleSupply(&lifecycleB{Value: 3}, Override()),
+					ModuleInvoke(func(i int) { invoked = append(invoked, "test") }),
+				),
+			},
+			extract:     new(lifecycleB),
+			want:        &lifecycleB{Value: 3},
+			wantInvoked: []string{"test"},
+		},
+		{
+			name: "error prefixed with module path",
+			modules: []ModuleOption{
+				Module("app",
+					ModuleSupply(1),
+					Module("db",
+						ModuleSupply(2),
+					),
+					ModuleInvoke(func(b *lifecycleB) { invoked = append(invoked, "app") }),
+				),
+			},
+			wantErr:        true,
+			wantErrContain: "[Module app/db] cannot provide function \"github.com/rectcircle/digpro\".TestContainerWrapper_Apply (",
+		},
+		{
+			name: "error invoke",
+			modules: []ModuleOption{
+				Module("app",
+					ModuleInvoke(func(b *lifecycleB) { invoked = append(invoked, "app") }),
+				),
+			},
+			wantErr:        true,
+			wantErrContain: "[Module app] missing dependencies for function",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invoked = nil
+			c := New()
+			err := c.Apply(tt.modules...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("c.Apply() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				if !strings.Contains(err.Error(), tt.wantErrContain) {
+					t.Errorf("c.Apply() error = %v, wantErrContain %s", err, tt.wantErrContain)
+				}
+				if !strings.Contains(err.Error(), "module_test.go:") {
+					t.Errorf("c.Apply() error = %v, want location of module_test.go", err)
+				}
+				return
+			}
+			got, err := c.Extract(tt.extract)
+			if err != nil {
+				t.Errorf("c.Extract() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("c.Extract() = %#v, want %#v", got, tt.want)
+			}
+			if !reflect.DeepEqual(invoked, tt.wantInvoked) {
+				t.Errorf("invoked = %#v, want %#v", invoked, tt.wantInvoked)
+			}
+		})
+	}
+}
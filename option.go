@@ -11,24 +11,48 @@ type overrideProvideOption struct {
 	dig.ProvideOption
 }
 
+type overrideGroupProvideOption struct {
+	dig.ProvideOption
+	location string // empty means all members
+}
+
 type resolveCyclicProvideOption struct {
 	dig.ProvideOption
 }
 
 type digproProvideOptions struct {
 	enableOverride      bool
+	overrideGroup       *overrideGroupProvideOption
 	enableResolveCyclic bool
 	locationFixCallSkip int
+	locationFixPC       uintptr
 }
 
 var overrideProvideOptionType = reflect.TypeOf(overrideProvideOption{})
+var overrideGroupProvideOptionType = reflect.TypeOf(overrideGroupProvideOption{})
 var resolveCyclicProvideOptionType = reflect.TypeOf(resolveCyclicProvideOption{})
 var locationFixOptionType = reflect.TypeOf(internal.LocationFixOption{})
+var lifecycleHookProvideOptionType = reflect.TypeOf(lifecycleHookProvideOption{})
+var managedLifecycleProvideOptionType = reflect.TypeOf(managedLifecycleProvideOption{})
 
 var digproProvideOptionTypeEnum = []reflect.Type{
 	overrideProvideOptionType,
+	overrideGroupProvideOptionType,
 	resolveCyclicProvideOptionType,
 	locationFixOptionType,
+	lifecycleHookProvideOptionType,
+	managedLifecycleProvideOptionType,
+	groupPriorityProvideOptionType,
+	groupMemberNameProvideOptionType,
+	envDefaultProvideOptionType,
+}
+
+// locationFix return the internal.LocationFixOption, use defaultCallSkip if no location fix option
+func (o digproProvideOptions) locationFix(defaultCallSkip int) internal.LocationFixOption {
+	if o.locationFixCallSkip == 0 {
+		return internal.LocationFixOption{CallSkip: defaultCallSkip, PC: o.locationFixPC}
+	}
+	return internal.LocationFixOption{CallSkip: o.locationFixCallSkip, PC: o.locationFixPC}
 }
 
 func filterProvideOptionAndGetDigproOptions(opts []dig.ProvideOption, excludes ...reflect.Type) ([]dig.ProvideOption, digproProvideOptions) {
@@ -47,10 +71,13 @@ func filterProvideOptionAndGetDigproOptions(opts []dig.ProvideOption, excludes .
 		}
 		if _, ok := opt.(overrideProvideOption); ok {
 			result.enableOverride = true
+		} else if o, ok := opt.(overrideGroupProvideOption); ok {
+			result.overrideGroup = &o
 		} else if _, ok := opt.(resolveCyclicProvideOption); ok {
 			result.enableResolveCyclic = true
 		} else if lfo, ok := opt.(internal.LocationFixOption); ok {
 			result.locationFixCallSkip = lfo.CallSkip
+			result.locationFixPC = lfo.PC
 		}
 	}
 	return filteredOpts, result
@@ -72,6 +99,7 @@ func filterInvokeOptionAndGetDigproOptions(opts []dig.InvokeOption, excludes ...
 		}
 		if lfo, ok := opt.(internal.LocationFixOption); ok {
 			result.locationFixCallSkip = lfo.CallSkip
+			result.locationFixPC = lfo.PC
 		}
 	}
 	return filteredOpts, result
@@ -4,8 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/rectcircle/digpro/internal"
+	"github.com/rectcircle/digpro/internal/digcopy"
 	"go.uber.org/dig"
 )
 
@@ -25,8 +27,9 @@ func Override() dig.ProvideOption {
 
 func overrideProvideMiddleware(pc *provideContext) error {
 
-	opts, digproOptResult := filterProvideOptionAndGetDigproOptions(pc.opts, overrideProvideOptionType)
+	opts, digproOptResult := filterProvideOptionAndGetDigproOptions(pc.opts, overrideProvideOptionType, overrideGroupProvideOptionType)
 	hasOverrideOpt := digproOptResult.enableOverride
+	overrideGroupOpt := digproOptResult.overrideGroup
 	pc.opts = opts
 
 	// get ProviderInfo
@@ -44,30 +47,51 @@ func overrideProvideMiddleware(pc *provideContext) error {
 	}
 
 	if hasGroupOpt && hasOverrideOpt {
-		return errors.New("cannot use digpro.Override() with value groups")
+		return errors.New("cannot use digpro.Override() with value groups, please use digpro.OverrideGroup() or digpro.OverrideGroupMember()")
 	}
-	if !hasOverrideOpt {
+	if overrideGroupOpt != nil && hasOverrideOpt {
+		return errors.New("cannot use digpro.Override() with digpro.OverrideGroup() or digpro.OverrideGroupMember()")
+	}
+	if !hasOverrideOpt && overrideGroupOpt == nil {
 		return pc.next()
 	}
 
 	// check and remove conflict provider
-	recoverOld, err := removeOldConflictProvideOutputs(pc.c, outputs)
+	removedBefore := make(map[int]struct{})
+	for i := range pc.c.provideInfos {
+		if pc.c.provideInfos[i].Removed {
+			removedBefore[i] = struct{}{}
+		}
+	}
+	var recoverOld func()
+	if overrideGroupOpt != nil {
+		recoverOld, err = removeOldGroupMembers(pc.c, outputs, overrideGroupOpt.location)
+	} else {
+		recoverOld, err = removeOldConflictProvideOutputs(pc.c, outputs)
+	}
 	if err != nil {
 		return err
 	}
 	err = pc.next()
 	if err != nil {
 		recoverOld()
+		return err
 	}
-
-	return err
+	// record override history
+	last := len(pc.c.provideInfos) - 1
+	for i := 0; i < last; i++ {
+		if _, ok := removedBefore[i]; !ok && pc.c.provideInfos[i].Removed {
+			pc.c.provideInfos[last].Overrides = append(pc.c.provideInfos[last].Overrides, i)
+		}
+	}
+	return nil
 }
 
 func removeOldConflictProvideOutputs(c *ContainerWrapper, outputs []internal.ProvideOutput) (recoverOld func(), err error) {
 	// check has conflict? if not will return error
 	index := -1
 	for i, info := range c.provideInfos {
-		if internal.EqualsProvideOutputs(info.ExportedOutputs(), outputs) {
+		if !info.Removed && internal.EqualsProvideOutputs(info.ExportedOutputs(), outputs) {
 			index = i
 			break
 		}
@@ -76,85 +100,229 @@ func removeOldConflictProvideOutputs(c *ContainerWrapper, outputs []internal.Pro
 		err = errors.New("no provider to override was found")
 		return
 	}
+	// node not allow called
+	if c.provideInfos[index].Called() {
+		err = fmt.Errorf("the old provider has called, digpro.Override only use before call Invoke()")
+		return
+	}
+	recoverOld = removeProviders(c, []int{index})
+	return
+}
 
-	containerValue := reflect.ValueOf(&c.Container).Elem()
+// OverrideGroup replace all registered members of the value group, the constructor should only provide value group members.
+// Only support digpro high level api (support *digpro.ContainerWrapper and digglobal).
+// if Container not exist member, the option will return error: no group member of xxx was found.
+//
+// for example
+//   c := digpro.New()
+//   _ = c.Supply("a", dig.Group("routes")) // please handle error in production
+//   _ = c.Supply("b", dig.Group("routes"))
+//   _ = c.Supply("c", dig.Group("routes"), digpro.OverrideGroup())
+//   routes, _ := c.Extract([]string{}, digpro.ExtractByGroup("routes"))
+//   fmt.Println(routes)
+//   // Output: [c]
+func OverrideGroup() dig.ProvideOption {
+	return overrideGroupProvideOption{}
+}
 
-	providersValue := internal.EnsureValueExported(containerValue.FieldByName("providers")) // map[dig.key][]*dig.node
-	nodesValue := internal.EnsureValueExported(containerValue.FieldByName("nodes"))         // []*node
+// OverrideGroupMember replace the registered members of the value group, which provided by the provider at location.
+// the constructor should only provide value group members.
+// Only support digpro high level api (support *digpro.ContainerWrapper and digglobal).
+// if Container not exist member, the option will return error: no group member of xxx was found.
+//
+// location support the following formats
+//   package.Function  // e.g. github.com/xxx/routes.NewHandler
+//   file:line         // e.g. routes.go:12 or github.com/xxx/routes/routes.go:12, the line is where the function is defined or the high level api is called
+//
+// for example
+//   // routes.go
+//   func NewHelloHandler() Handler { ... }
+//   // main.go
+//   _ = c.Provide(routes.NewHelloHandler, dig.Group("routes")) // please handle error in production
+//   _ = c.Provide(routes.NewWorldHandler, dig.Group("routes"))
+//   // main_test.go
+//   _ = c.Provide(NewMockHelloHandler, dig.Group("routes"), digpro.OverrideGroupMember("github.com/xxx/routes.NewHelloHandler"))
+func OverrideGroupMember(location string) dig.ProvideOption {
+	return overrideGroupProvideOption{location: location}
+}
 
-	// create all keys
-	keyType := providersValue.Type().Key()
-	keys := []reflect.Value{} // dig.key
+// RemoveGroupMembers remove the registered members (type of typ) of the value group, which provided by the provider at locations,
+// if locations is empty, all members of the value group will be removed.
+// The format of location see digpro.OverrideGroupMember.
+//
+// for example
+//   c := digpro.New()
+//   _ = c.Supply("a", dig.Group("routes")) // please handle error in production
+//   _ = c.Supply("b", dig.Group("routes"))
+//   _ = c.RemoveGroupMembers("", "routes")
+//   routes, _ := c.Extract([]string{}, digpro.ExtractByGroup("routes"))
+//   fmt.Println(len(routes.([]string)))
+//   // Output: 0
+func (c *ContainerWrapper) RemoveGroupMembers(typ interface{}, group string, locations ...string) error {
+	c.syncParent()
+	t, err := extractTypeOf(typ)
+	if err != nil {
+		return wrapError("RemoveGroupMembers", err)
+	}
+	if err := c.removeGroupMembers(t, group, locations...); err != nil {
+		return wrapError("RemoveGroupMembers", err)
+	}
+	c.recordCall(func(clone *ContainerWrapper) error {
+		return clone.RemoveGroupMembers(typ, group, locations...)
+	})
+	return nil
+}
+
+func (c *ContainerWrapper) removeGroupMembers(t reflect.Type, group string, locations ...string) error {
+	if len(locations) == 0 {
+		locations = []string{""}
+	}
+	outputs := []internal.ProvideOutput{{Type: t, Group: group}}
+	recoverOlds := []func(){}
+	for _, location := range locations {
+		recoverOld, err := removeOldGroupMembers(c, outputs, location)
+		if err != nil {
+			for i := len(recoverOlds) - 1; i >= 0; i-- {
+				recoverOlds[i]()
+			}
+			return err
+		}
+		recoverOlds = append(recoverOlds, recoverOld)
+	}
+	return nil
+}
+
+// extractTypeOf return the type of value will be extracted by typ, see digpro.Extract
+func extractTypeOf(typ interface{}) (reflect.Type, error) {
+	if typ == nil {
+		return nil, errors.New("typ should not be untyped nil")
+	}
+	return internal.ExtractTypeOf(typ), nil
+}
+
+// matchLocation check the provider location matches location, the format of location see digpro.OverrideGroupMember
+func matchLocation(f *digcopy.Func, location string) bool {
+	if f == nil {
+		return false
+	}
+	if location == f.Package+"."+f.Name {
+		return true
+	}
+	fileLine := fmt.Sprintf("%s:%d", f.File, f.Line)
+	return location == fileLine || strings.HasSuffix(fileLine, "/"+location)
+}
+
+// removeOldGroupMembers remove the providers of outputs (all are value group members) at location (empty means all)
+func removeOldGroupMembers(c *ContainerWrapper, outputs []internal.ProvideOutput, location string) (recoverOld func(), err error) {
+	groupOutputs := make(map[internal.ProvideOutput]struct{}, len(outputs))
 	for _, output := range outputs {
-		keyValue := reflect.New(keyType).Elem()
-		internal.EnsureValueExported(keyValue.FieldByName("t")).Set(reflect.ValueOf(output.Type))
-		internal.EnsureValueExported(keyValue.FieldByName("name")).Set(reflect.ValueOf(output.Name))
-		internal.EnsureValueExported(keyValue.FieldByName("group")).Set(reflect.ValueOf(output.Group))
-		keys = append(keys, keyValue)
-	}
-	// get all keyNodes
-	keyNodes := []reflect.Value{} // []*dig.node
-	for i, key := range keys {
-		node := providersValue.MapIndex(key)
-		if !node.IsValid() {
-			// dead code
-			err = fmt.Errorf("no provider to override was found: [%d].%s", i, outputs[i])
+		if output.Group == "" {
+			err = fmt.Errorf("digpro.OverrideGroup() or digpro.OverrideGroupMember() only support value groups, but got %s", output.String())
 			return
 		}
-		keyNodes = append(keyNodes, node)
-	}
-	// check old outputs from same node
-	var finalNodes *reflect.Value // []*dig.node
-	for i, node := range keyNodes {
-		if finalNodes == nil {
-			finalNodes = &node
-		} else {
-			if !reflect.DeepEqual(finalNodes.Interface(), node.Interface()) {
-				// dead code
-				err = fmt.Errorf("the registered provider of [%d].%s is different from other outputs", i, outputs[i])
-				return
+		groupOutputs[output] = struct{}{}
+	}
+	indexes := []int{}
+	for i := range c.provideInfos {
+		info := &c.provideInfos[i]
+		if info.Removed || (location != "" && !matchLocation(info.Location(), location)) {
+			continue
+		}
+		matched, others := false, []string{}
+		for _, output := range info.ExportedOutputs() {
+			if _, ok := groupOutputs[output]; ok {
+				matched = true
+			} else {
+				others = append(others, output.String())
 			}
 		}
+		if !matched {
+			continue
+		}
+		if len(others) != 0 {
+			err = fmt.Errorf("the old group member provider %v also provides %s, can not be removed", info.Location(), strings.Join(others, ", "))
+			return
+		}
+		if info.Called() {
+			err = fmt.Errorf("the old group member provider %v has called, only can be removed before call Invoke()", info.Location())
+			return
+		}
+		indexes = append(indexes, i)
 	}
-	if finalNodes == nil {
-		// dead code
-		err = errors.New("no provider to override was found")
+	if len(indexes) == 0 {
+		err = fmt.Errorf("no group member of %s was found", outputsString(outputs))
 		return
 	}
-	if finalNodes.Len() != 1 {
-		// dead code
-		err = errors.New("unknown error: len(finalNode) != 1")
-		return
+	recoverOld = removeProviders(c, indexes)
+	return
+}
+
+func outputsString(outputs []internal.ProvideOutput) string {
+	s := make([]string, 0, len(outputs))
+	for _, output := range outputs {
+		s = append(s, output.String())
 	}
-	finalNode := finalNodes.Index(0).Elem() // dig.node
-	// node not allow called
-	if internal.EnsureValueExported(finalNode.FieldByName("called")).Interface().(bool) {
-		err = fmt.Errorf("the old provider has called, digpro.Override only use before call Invoke()")
-		return
+	return strings.Join(s, ", ")
+}
+
+// removeProviders remove the nodes of c.provideInfos[indexes] from dig.Container, and mark them as removed.
+// return a function to recover them.
+func removeProviders(c *ContainerWrapper, indexes []int) (recoverOld func()) {
+	containerValue := reflect.ValueOf(&c.Container).Elem()
+
+	providersValue := internal.EnsureValueExported(containerValue.FieldByName("providers")) // map[dig.key][]*dig.node
+	nodesValue := internal.EnsureValueExported(containerValue.FieldByName("nodes"))         // []*node
+
+	removedNodes := make(map[uintptr]struct{}, len(indexes))
+	for _, i := range indexes {
+		removedNodes[c.provideInfos[i].Node.Pointer()] = struct{}{}
+	}
+	filterNodes := func(nodes reflect.Value) reflect.Value {
+		result := reflect.MakeSlice(nodes.Type(), 0, nodes.Len())
+		for i := 0; i < nodes.Len(); i++ {
+			if _, ok := removedNodes[nodes.Index(i).Pointer()]; !ok {
+				result = reflect.Append(result, nodes.Index(i))
+			}
+		}
+		return result
 	}
 
 	// delete nodes
-	oldNodes := reflect.MakeSlice(nodesValue.Type(), 0, nodesValue.Len())
-	newNodes := reflect.MakeSlice(nodesValue.Type(), 0, nodesValue.Len()-1)
-	for i := 0; i < nodesValue.Len(); i++ {
-		if nodesValue.Index(i).Elem() != finalNode {
-			reflect.Append(newNodes, nodesValue.Index(i))
-		} else {
-			reflect.Append(oldNodes, nodesValue.Index(i))
+	oldNodes := nodesValue.Interface()
+	nodesValue.Set(filterNodes(nodesValue))
+	// delete providers
+	keyType := providersValue.Type().Key()
+	oldProviders := map[interface{}]reflect.Value{} // dig.key -> []*dig.node
+	for _, i := range indexes {
+		for _, output := range c.provideInfos[i].ExportedOutputs() {
+			key := reflect.New(keyType).Elem()
+			internal.EnsureValueExported(key.FieldByName("t")).Set(reflect.ValueOf(output.Type))
+			internal.EnsureValueExported(key.FieldByName("name")).Set(reflect.ValueOf(output.Name))
+			internal.EnsureValueExported(key.FieldByName("group")).Set(reflect.ValueOf(output.Group))
+			nodes := providersValue.MapIndex(key)
+			if !nodes.IsValid() {
+				continue
+			}
+			if _, ok := oldProviders[key.Interface()]; !ok {
+				oldProviders[key.Interface()] = nodes
+			}
+			if nodes = filterNodes(nodes); nodes.Len() == 0 {
+				providersValue.SetMapIndex(key, reflect.Value{})
+			} else {
+				providersValue.SetMapIndex(key, nodes)
+			}
 		}
+		c.provideInfos[i].Removed = true
 	}
-	nodesValue.Set(newNodes)
-	// delete container
-	for _, key := range keys {
-		providersValue.SetMapIndex(key, reflect.Value{})
-	}
-	recoverOld = func() {
-		// recover node
-		nodesValue.Set(oldNodes)
-		// recover container
-		for _, key := range keys {
-			providersValue.SetMapIndex(key, *finalNodes)
+	return func() {
+		// recover nodes
+		nodesValue.Set(reflect.ValueOf(oldNodes))
+		// recover providers
+		for key, nodes := range oldProviders {
+			providersValue.SetMapIndex(reflect.ValueOf(key), nodes)
+		}
+		for _, i := range indexes {
+			c.provideInfos[i].Removed = false
 		}
 	}
-	return
 }
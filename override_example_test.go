@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/rectcircle/digpro"
+	"go.uber.org/dig"
 )
 
 func ExampleOverride() {
@@ -15,3 +16,23 @@ func ExampleOverride() {
 	fmt.Println(i.(int) == 1)
 	// Output: true
 }
+
+func ExampleOverrideGroup() {
+	c := digpro.New()
+	_ = c.Supply("a", dig.Group("routes")) // please handle error in production
+	_ = c.Supply("b", dig.Group("routes"))
+	_ = c.Supply("c", dig.Group("routes"), digpro.OverrideGroup())
+	routes, _ := c.Extract([]string{}, digpro.ExtractByGroup("routes"))
+	fmt.Println(routes)
+	// Output: [c]
+}
+
+func ExampleContainerWrapper_RemoveGroupMembers() {
+	c := digpro.New()
+	_ = c.Supply("a", dig.Group("routes")) // please handle error in production
+	_ = c.Supply("b", dig.Group("routes"))
+	_ = c.RemoveGroupMembers("", "routes")
+	routes, _ := c.Extract([]string{}, digpro.ExtractByGroup("routes"))
+	fmt.Println(len(routes.([]string)))
+	// Output: 0
+}
@@ -1,7 +1,12 @@
 package digpro
 
 import (
+	"errors"
+	"fmt"
 	"reflect"
+	"runtime"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/rectcircle/digpro/internal/tests"
@@ -175,3 +180,247 @@ func TestOverride(t *testing.T) {
 		})
 	}
 }
+
+func overrideGroupA() string { return "a" }
+
+func overrideGroupB() string { return "b" }
+
+type overrideGroupOut struct {
+	dig.Out
+	Value string `group:"g"`
+	Other int
+}
+
+func TestOverrideGroup(t *testing.T) {
+	type args struct {
+		prepare PrepareFunc
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+		want    []string
+	}{
+		{
+			name: "replace all members",
+			args: args{
+				prepare: func(c *ContainerWrapper) error {
+					return firstError(
+						c.Provide(overrideGroupA, dig.Group("g")),
+						c.Provide(overrideGroupB, dig.Group("g")),
+						c.Supply(1, dig.Group("g")),
+						c.Supply("c", dig.Group("g"), OverrideGroup()),
+					)
+				},
+			},
+			want: []string{"c"},
+		},
+		{
+			name: "replace member by function name",
+			args: args{
+				prepare: func(c *ContainerWrapper) error {
+					return firstError(
+						c.Provide(overrideGroupA, dig.Group("g")),
+						c.Provide(overrideGroupB, dig.Group("g")),
+						c.Supply("c", dig.Group("g"), OverrideGroupMember("github.com/rectcircle/digpro.overrideGroupA")),
+					)
+				},
+			},
+			want: []string{"b", "c"},
+		},
+		{
+			name: "replace member by file and line",
+			args: args{
+				prepare: func(c *ContainerWrapper) error {
+					_, _, line, _ := runtime.Caller(0)
+					return firstError(
+						c.Supply("a", dig.Group("g")),
+						c.Supply("b", dig.Group("g")),
+						c.Supply("c", dig.Group("g"), OverrideGroupMember(fmt.Sprintf("override_test.go:%d", line+3))),
+					)
+				},
+			},
+			want: []string{"a", "c"},
+		},
+		{
+			name: "error override and override group",
+			args: args{
+				prepare: func(c *ContainerWrapper) error {
+					return firstError(
+						c.Supply("a", dig.Group("g")),
+						c.Supply("c", dig.Group("g"), OverrideGroup(), Override()),
+					)
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "error not group",
+			args: args{
+				prepare: func(c *ContainerWrapper) error {
+					return firstError(
+						c.Supply("a"),
+						c.Supply("c", OverrideGroup()),
+					)
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "error no member found",
+			args: args{
+				prepare: func(c *ContainerWrapper) error {
+					return firstError(
+						c.Provide(overrideGroupA, dig.Group("g")),
+						c.Supply("c", dig.Group("g"), OverrideGroupMember("github.com/rectcircle/digpro.overrideGroupB")),
+					)
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "error member provider provides others",
+			args: args{
+				prepare: func(c *ContainerWrapper) error {
+					return firstError(
+						c.Provide(func() overrideGroupOut { return overrideGroupOut{Value: "a"} }),
+						c.Supply("c", dig.Group("g"), OverrideGroup()),
+					)
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "error member provider has called",
+			args: args{
+				prepare: func(c *ContainerWrapper) error {
+					return firstError(
+						c.Provide(overrideGroupA, dig.Group("g")),
+						c.Invoke(func(in struct {
+							dig.In
+							Values []string `group:"g"`
+						}) {
+						}),
+						c.Supply("c", dig.Group("g"), OverrideGroup()),
+					)
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "remove all members",
+			args: args{
+				prepare: func(c *ContainerWrapper) error {
+					return firstError(
+						c.Provide(overrideGroupA, dig.Group("g")),
+						c.Provide(overrideGroupB, dig.Group("g")),
+						c.RemoveGroupMembers("", "g"),
+					)
+				},
+			},
+			want: []string{},
+		},
+		{
+			name: "remove member by location",
+			args: args{
+				prepare: func(c *ContainerWrapper) error {
+					return firstError(
+						c.Provide(overrideGroupA, dig.Group("g")),
+						c.Provide(overrideGroupB, dig.Group("g")),
+						c.RemoveGroupMembers("", "g", "github.com/rectcircle/digpro.overrideGroupB"),
+					)
+				},
+			},
+			want: []string{"a"},
+		},
+		{
+			name: "error remove member recover",
+			args: args{
+				prepare: func(c *ContainerWrapper) error {
+					err := c.RemoveGroupMembers("", "g", "github.com/rectcircle/digpro.overrideGroupB", "github.com/rectcircle/digpro.overrideGroupB")
+					if err == nil {
+						return errors.New("want error")
+					}
+					return nil
+				},
+			},
+			want: []string{"a", "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+			if strings.HasPrefix(tt.name, "error remove") {
+				if err := firstError(
+					c.Provide(overrideGroupA, dig.Group("g")),
+					c.Provide(overrideGroupB, dig.Group("g")),
+				); err != nil {
+					t.Errorf("c.Provide() error = %v", err)
+					return
+				}
+			}
+			err := tt.args.prepare(c)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("prepare() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			got, err := c.Extract([]string{}, ExtractByGroup("g"))
+			if err != nil {
+				t.Errorf("c.Extract() error = %v", err)
+				return
+			}
+			sort.Strings(got.([]string))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("c.Extract() got = %#v, want = %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOverride_removeNode(t *testing.T) {
+	c := New()
+	if err := firstError(
+		c.Supply(true),
+		c.Supply(1),
+		c.Supply(2, Override()),
+	); err != nil {
+		t.Errorf("prepare error = %v", err)
+		return
+	}
+	nodes := reflect.ValueOf(&c.Container).Elem().FieldByName("nodes")
+	// Lifecycle, bool and the new int
+	if nodes.Len() != 3 {
+		t.Errorf("len(nodes) got = %d, want = 3", nodes.Len())
+	}
+	if !c.provideInfos[1].Removed || c.provideInfos[0].Removed || c.provideInfos[2].Removed {
+		t.Errorf("provideInfos removed flags are wrong")
+	}
+}
+
+func TestOverride_keepOtherNodes(t *testing.T) {
+	c := New()
+	if err := firstError(
+		c.Supply(true),
+		c.Supply(1),
+		c.Supply(2, Override()),
+	); err != nil {
+		t.Errorf("prepare error = %v", err)
+		return
+	}
+	nodes := reflect.ValueOf(&c.Container).Elem().FieldByName("nodes")
+	// Lifecycle, bool and the new int
+	if nodes.Len() != 3 {
+		t.Errorf("len(nodes) got = %d, want = 3", nodes.Len())
+	}
+	// recover all nodes when the override failed
+	err := c.Provide(func() (int, bool) { return 3, false }, Override())
+	if err == nil {
+		t.Errorf("c.Provide() error = nil, wantErr")
+	}
+	if nodes.Len() != 3 {
+		t.Errorf("len(nodes) got = %d after recover, want = 3", nodes.Len())
+	}
+}
@@ -1,9 +1,11 @@
 package digpro
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 	"unsafe"
 )
 
@@ -160,4 +162,47 @@ type D5 struct {
 	a string `optional:"true"`
 }
 
+type PostConstructFoo struct {
+	A int
+	B int `digpro:"ignore"`
+}
+
+func (p *PostConstructFoo) PostConstruct() error {
+	if p.A < 0 {
+		return errors.New("PostConstructFoo.A must >= 0")
+	}
+	p.B = p.A * 2
+	return nil
+}
+
+type D6 struct {
+	D7    *D7
+	Value string
+	Desc  string `digpro:"ignore"`
+}
+
+func (d6 *D6) PostConstruct() error {
+	if d6.D7 == nil || d6.D7.D6 != d6 {
+		return errors.New("D6.D7 not injected")
+	}
+	if d6.Value == "" {
+		return errors.New("D6.Value is required")
+	}
+	d6.Desc = fmt.Sprintf("D6: {D7: {D6: ..., Value: %d}, Value: '%s'}", d6.D7.Value, d6.Value)
+	return nil
+}
+
+type D7 struct {
+	D6    *D6
+	Value int
+}
+
+type DefaultFoo struct {
+	A int           `optional:"true" default:"1"`
+	B string        `name:"b" optional:"true" default:"b"`
+	C time.Duration `optional:"true" default:"1s"`
+	D []string      `optional:"true" default:"a,b"`
+	E bool          `optional:"true"`
+}
+
 type PrepareFunc func(c *ContainerWrapper) error
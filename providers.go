@@ -0,0 +1,90 @@
+package digpro
+
+import (
+	"github.com/rectcircle/digpro/internal"
+	"github.com/rectcircle/digpro/internal/digcopy"
+)
+
+// ProviderKind is how the provider is registered
+type ProviderKind = internal.ProviderKind
+
+const (
+	// ProviderKindProvide is registered by Provide
+	ProviderKindProvide = internal.ProviderKindProvide
+	// ProviderKindStruct is registered by Struct
+	ProviderKindStruct = internal.ProviderKindStruct
+	// ProviderKindSupply is registered by Supply, SupplyEnv or Config
+	ProviderKindSupply = internal.ProviderKindSupply
+	// ProviderKindBridge is registered by Scope, which extract the output from parent container
+	ProviderKindBridge = internal.ProviderKindBridge
+)
+
+// ProvideInput is the input of provider
+type ProvideInput = internal.ProvideInput
+
+// ProvideOutput is the output of provider
+type ProvideOutput = internal.ProvideOutput
+
+// Location is the source location of provider, print it by fmt.Sprint
+type Location = digcopy.Func
+
+// ProviderDescriptor describe a registered provider, the modification of it will not affect the container
+type ProviderDescriptor struct {
+	// ID is the index of provider in registration order, is unique in the container
+	ID   int
+	Kind ProviderKind
+	// Inputs include the fields of digpro.ResolveCyclic Struct
+	Inputs   []ProvideInput
+	Outputs  []ProvideOutput
+	Location *Location
+	// Removed is true if the provider is removed by digpro.Override, Remove or RemoveGroupMembers
+	Removed bool
+	// Overrides is the IDs of the providers which are overridden by this provider
+	Overrides []int
+	// OverriddenBy is the ID of the provider which override this provider, -1 means not overridden
+	OverriddenBy int
+	// ResolveCyclic is true if the provider is registered with digpro.ResolveCyclic()
+	ResolveCyclic bool
+	// Called is true if the constructor has been called
+	Called bool
+}
+
+// Providers return the descriptors of all registered providers (include removed) in registration order,
+// for building linters or dashboards.
+//
+// for example
+//   c := digpro.New()
+//   _ = c.Supply(1) // please handle error in production
+//   _ = c.Supply(2, digpro.Override())
+//   for _, p := range c.Providers() {
+//   	fmt.Println(p.ID, p.Kind, p.Outputs[0].String(), p.Removed, p.Overrides, p.OverriddenBy)
+//   }
+//   // Output:
+//   // 0 Supply int true [] 1
+//   // 1 Supply int false [0] -1
+func (c *ContainerWrapper) Providers() []ProviderDescriptor {
+	c.syncParent()
+	result := make([]ProviderDescriptor, 0, len(c.provideInfos))
+	for i := range c.provideInfos {
+		info := &c.provideInfos[i]
+		descriptor := ProviderDescriptor{
+			ID:            i,
+			Kind:          info.Kind,
+			Inputs:        append([]ProvideInput{}, c.providerInputs(info)...),
+			Outputs:       append([]ProvideOutput{}, info.ExportedOutputs()...),
+			Location:      info.Location(),
+			Removed:       info.Removed,
+			Overrides:     append([]int{}, info.Overrides...),
+			OverriddenBy:  -1,
+			ResolveCyclic: info.ResolveCyclic,
+			Called:        info.Called(),
+		}
+		result = append(result, descriptor)
+	}
+	for _, descriptor := range result {
+		for _, id := range descriptor.Overrides {
+			result[id].OverriddenBy = descriptor.ID
+		}
+	}
+	return result
+}
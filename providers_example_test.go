@@ -0,0 +1,19 @@
+package digpro_test
+
+import (
+	"fmt"
+
+	"github.com/rectcircle/digpro"
+)
+
+func ExampleContainerWrapper_Providers() {
+	c := digpro.New()
+	_ = c.Supply(1) // please handle error in production
+	_ = c.Supply(2, digpro.Override())
+	for _, p := range c.Providers() {
+		fmt.Println(p.ID, p.Kind, p.Outputs[0].String(), p.Removed, p.Overrides, p.OverriddenBy)
+	}
+	// Output:
+	// 0 Supply int true [] 1
+	// 1 Supply int false [0] -1
+}
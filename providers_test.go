@@ -0,0 +1,179 @@
+package digpro
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.uber.org/dig"
+)
+
+func TestContainerWrapper_Providers(t *testing.T) {
+	type want struct {
+		Kind          ProviderKind
+		Inputs        []ProvideInput
+		Outputs       []ProvideOutput
+		Removed       bool
+		Overrides     []int
+		OverriddenBy  int
+		ResolveCyclic bool
+		Called        bool
+	}
+	tests := []struct {
+		name    string
+		prepare PrepareFunc
+		scope   bool
+		want    []want
+	}{
+		{
+			name: "kind",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Provide(func(i int) string { return "a" }, dig.Name("a")),
+					c.Struct(new(verifyFoo)),
+					c.Supply(1),
+					c.Config("", map[string]interface{}{"a": 1}, struct{ A int }{}),
+				)
+			},
+			want: []want{
+				{
+					Kind:         ProviderKindProvide,
+					Inputs:       []ProvideInput{{Type: reflect.TypeOf(0)}},
+					Outputs:      []ProvideOutput{{Type: reflect.TypeOf(""), Name: "a"}},
+					Overrides:    []int{},
+					OverriddenBy: -1,
+				},
+				{
+					Kind:         ProviderKindStruct,
+					Inputs:       []ProvideInput{{Type: reflect.TypeOf(""), Name: "a"}, {Type: reflect.TypeOf(0), Name: "b"}},
+					Outputs:      []ProvideOutput{{Type: reflect.TypeOf(new(verifyFoo))}},
+					Overrides:    []int{},
+					OverriddenBy: -1,
+				},
+				{
+					Kind:         ProviderKindSupply,
+					Inputs:       []ProvideInput{},
+					Outputs:      []ProvideOutput{{Type: reflect.TypeOf(0)}},
+					Overrides:    []int{},
+					OverriddenBy: -1,
+				},
+				{
+					Kind:         ProviderKindSupply,
+					Inputs:       []ProvideInput{},
+					Outputs:      []ProvideOutput{{Type: reflect.TypeOf(struct{ A int }{})}, {Type: reflect.TypeOf(0), Name: "a"}},
+					Overrides:    []int{},
+					OverriddenBy: -1,
+				},
+			},
+		},
+		{
+			name: "override and called",
+			prepare: func(c *ContainerWrapper) error {
+				if err := firstError(
+					c.Supply(1),
+					c.Supply(2, Override()),
+					c.Supply("a", dig.Group("g")),
+					c.Supply("b", dig.Group("g"), OverrideGroup()),
+				); err != nil {
+					return err
+				}
+				_, err := c.Extract(0)
+				return err
+			},
+			want: []want{
+				{Kind: ProviderKindSupply, Inputs: []ProvideInput{}, Outputs: []ProvideOutput{{Type: reflect.TypeOf(0)}}, Removed: true, Overrides: []int{}, OverriddenBy: 1},
+				{Kind: ProviderKindSupply, Inputs: []ProvideInput{}, Outputs: []ProvideOutput{{Type: reflect.TypeOf(0)}}, Overrides: []int{0}, OverriddenBy: -1, Called: true},
+				{Kind: ProviderKindSupply, Inputs: []ProvideInput{}, Outputs: []ProvideOutput{{Type: reflect.TypeOf(""), Group: "g"}}, Removed: true, Overrides: []int{}, OverriddenBy: 3},
+				{Kind: ProviderKindSupply, Inputs: []ProvideInput{}, Outputs: []ProvideOutput{{Type: reflect.TypeOf(""), Group: "g"}}, Overrides: []int{2}, OverriddenBy: -1},
+			},
+		},
+		{
+			name: "resolve cyclic",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Struct(new(D1), ResolveCyclic()),
+					c.Provide(func(d1 *D1) *D2 { return &D2{D1: d1} }, ResolveCyclic()),
+				)
+			},
+			want: []want{
+				{
+					Kind:          ProviderKindStruct,
+					Inputs:        []ProvideInput{{Type: reflect.TypeOf(new(D2))}, {Type: reflect.TypeOf(0)}},
+					Outputs:       []ProvideOutput{{Type: reflect.TypeOf(new(D1))}},
+					Overrides:     []int{},
+					OverriddenBy:  -1,
+					ResolveCyclic: true,
+				},
+				{
+					Kind:          ProviderKindProvide,
+					Inputs:        []ProvideInput{{Type: reflect.TypeOf(new(D1))}},
+					Outputs:       []ProvideOutput{{Type: reflect.TypeOf(new(D2))}},
+					Overrides:     []int{},
+					OverriddenBy:  -1,
+					ResolveCyclic: true,
+				},
+			},
+		},
+		{
+			name: "scope",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.parent.Supply(1),
+					c.Supply("a"),
+				)
+			},
+			scope: true,
+			want: []want{
+				{Kind: ProviderKindBridge, Inputs: []ProvideInput{}, Outputs: []ProvideOutput{{Type: reflect.TypeOf(0)}}, Overrides: []int{}, OverriddenBy: -1},
+				{Kind: ProviderKindSupply, Inputs: []ProvideInput{}, Outputs: []ProvideOutput{{Type: reflect.TypeOf("")}}, Overrides: []int{}, OverriddenBy: -1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+			if tt.scope {
+				c = c.Scope("child")
+			}
+			if err := tt.prepare(c); err != nil {
+				t.Errorf("prepare() error = %v", err)
+				return
+			}
+			providers := c.Providers()
+			got := make([]want, 0, len(providers))
+			for i, p := range providers {
+				if p.ID != i {
+					t.Errorf("c.Providers()[%d].ID = %d", i, p.ID)
+				}
+				if p.Location == nil || !strings.HasSuffix(p.Location.File, "providers_test.go") {
+					t.Errorf("c.Providers()[%d].Location = %v, want in providers_test.go", i, p.Location)
+				}
+				got = append(got, want{
+					Kind:          p.Kind,
+					Inputs:        p.Inputs,
+					Outputs:       p.Outputs,
+					Removed:       p.Removed,
+					Overrides:     p.Overrides,
+					OverriddenBy:  p.OverriddenBy,
+					ResolveCyclic: p.ResolveCyclic,
+					Called:        p.Called,
+				})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("c.Providers() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContainerWrapper_Providers_readOnly(t *testing.T) {
+	c := New()
+	if err := c.Provide(func(i int) string { return "a" }); err != nil {
+		t.Errorf("prepare() error = %v", err)
+		return
+	}
+	c.Providers()[0].Inputs[0].Name = "changed"
+	if got := c.Providers()[0].Inputs[0].Name; got != "" {
+		t.Errorf("c.Providers()[0].Inputs[0].Name = %s, want empty", got)
+	}
+}
@@ -91,7 +91,40 @@ func makeParameterObjectType(structOrStructPtr interface{}, resolveCyclic bool)
 	return reflect.StructOf(parameterObjectFields), fieldMapping, nil
 }
 
-func copyFromParameterObject(structOrStructPtr interface{}, parameterObjectValue reflect.Value, fieldMapping map[string]int) (interface{}, error) {
+// structDefault is the default value of an optional field, and the output the field depends on
+type structDefault struct {
+	value  reflect.Value
+	output internal.ProvideOutput
+}
+
+// makeStructDefaults parse `default:"..."` tag of optional fields, return map[valueFieldIndex]structDefault
+func makeStructDefaults(structOrStructPtr interface{}) (map[int]structDefault, error) {
+	_, structTyp, err := structTypeOf(structOrStructPtr)
+	if err != nil {
+		return nil, err
+	}
+	defaults := map[int]structDefault{}
+	for i := 0; i < structTyp.NumField(); i++ {
+		f := structTyp.Field(i)
+		defaultValue, ok := f.Tag.Lookup(internal.DefaultTag)
+		if !ok {
+			continue
+		}
+		if f.Tag.Get("digpro") == "ignore" || f.Tag.Get(internal.DigGroupTag) != "" || f.Tag.Get(internal.DigOptionalTag) != "true" {
+			return nil, fmt.Errorf("field %s:%s has default tag, default tag only support optional field (without group tag and digpro ignore tag)", f.Name, f.Type)
+		}
+		value, err := internal.ParseValue(f.Type, defaultValue)
+		if err != nil {
+			return nil, fmt.Errorf("field %s:%s default value %q is invalid: %w", f.Name, f.Type, defaultValue, err)
+		}
+		defaults[i] = structDefault{value: value, output: internal.ProvideOutput{Type: f.Type, Name: f.Tag.Get(internal.DigNameTag)}}
+	}
+	return defaults, nil
+}
+
+// copyFromParameterObject copy the fields of parameter object to structOrStructPtr,
+// provided report whether the optional dependency is provided, it must not be nil if defaults is not empty
+func copyFromParameterObject(structOrStructPtr interface{}, parameterObjectValue reflect.Value, fieldMapping map[string]int, defaults map[int]structDefault, provided func(output internal.ProvideOutput) bool) (interface{}, error) {
 	isPtr, structPtrValue, err := structPtrValueOf(structOrStructPtr)
 	if err != nil {
 		return nil, err
@@ -110,6 +143,13 @@ func copyFromParameterObject(structOrStructPtr interface{}, parameterObjectValue
 		}
 		structFieldValue.Set(parameterObjectFieldValue)
 	}
+	// optional dependency is missing, use default value
+	for structFieldIndex, d := range defaults {
+		structFieldValue := internal.EnsureValueExported(addressableStructValue.Field(structFieldIndex))
+		if !provided(d.output) {
+			structFieldValue.Set(d.value)
+		}
+	}
 	if isPtr {
 		return structPtrValue.Interface(), nil
 	}
@@ -97,7 +97,7 @@ func Test_makeParameterObjectType_copyFromParameterObject(t *testing.T) {
 			if err != nil {
 				return
 			}
-			injectedValue, err := copyFromParameterObject(tt.args.structOrStructPtr, mockParameterObject(parameterObjectType), fieldMapping)
+			injectedValue, err := copyFromParameterObject(tt.args.structOrStructPtr, mockParameterObject(parameterObjectType), fieldMapping, nil, nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("copyFromParameterObject() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -157,7 +157,7 @@ func Test_copyFromParameterObject(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := copyFromParameterObject(tt.args.structOrStructPtr, tt.args.parameterObjectValue, tt.args.fieldMapping)
+			got, err := copyFromParameterObject(tt.args.structOrStructPtr, tt.args.parameterObjectValue, tt.args.fieldMapping, nil, nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("copyFromParameterObject() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -0,0 +1,119 @@
+package digpro
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/rectcircle/digpro/internal"
+)
+
+// Remove a registered provider by output key (type of typ, and the name of digpro.ExtractByName),
+// all outputs of the provider will be removed. if digpro.ExtractByGroup is specified, all members of the group will be removed.
+// The typ look like Extract, for example int(0) for int, new(A) for interface A.
+//
+// Remove will return error when
+//   * no provider of the output key was found
+//   * the provider has been called (Remove only use before call Invoke())
+//   * other registered providers depend on the outputs of the provider (include the fields of digpro.ResolveCyclic Struct and digpro.Lazy[T] inputs)
+//
+// Note: the providers of child containers (see Scope) will not be checked.
+//
+// for example
+//   c := digpro.New()
+//   _ = c.Supply(1) // please handle error in production
+//   _ = c.Remove(int(0))
+//   _, err := c.Extract(int(0))
+//   fmt.Println(err != nil)
+//   // Output: true
+func (c *ContainerWrapper) Remove(typ interface{}, opts ...ExtractOption) error {
+	c.syncParent()
+	t, err := extractTypeOf(typ)
+	if err != nil {
+		return wrapError("Remove", err)
+	}
+	options := internal.ApplyExtractOptions(opts...)
+	if options.Group != "" {
+		// same as Extract, typ should be slice of member type
+		if t.Kind() != reflect.Slice {
+			return wrapError("Remove", fmt.Errorf("typ should be slice when use digpro.ExtractByGroup, but got %s", t))
+		}
+		if err := c.removeGroupMembers(t.Elem(), options.Group); err != nil {
+			return wrapError("Remove", err)
+		}
+		c.recordCall(func(clone *ContainerWrapper) error {
+			return clone.Remove(typ, opts...)
+		})
+		return nil
+	}
+	key := internal.ProvideOutput{Type: t, Name: options.Name}
+
+	// find provider
+	index := -1
+	for i := range c.provideInfos {
+		if c.provideInfos[i].Removed {
+			continue
+		}
+		for _, output := range c.provideInfos[i].ExportedOutputs() {
+			if output == key {
+				index = i
+				break
+			}
+		}
+		if index != -1 {
+			break
+		}
+	}
+	if index == -1 {
+		return wrapError("Remove", fmt.Errorf("no provider of %s was found", key.String()))
+	}
+	info := &c.provideInfos[index]
+	if info.Called() {
+		return wrapError("Remove", fmt.Errorf("the provider %v of %s has called, only can be removed before call Invoke()", info.Location(), key.String()))
+	}
+
+	// check dependents
+	outputs := make(map[internal.ProvideOutput]struct{}, len(info.ExportedOutputs()))
+	for _, output := range info.ExportedOutputs() {
+		outputs[output] = struct{}{}
+	}
+	dependents := []string{}
+	for i := range c.provideInfos {
+		if i == index || c.provideInfos[i].Removed {
+			continue
+		}
+		for _, input := range c.providerInputs(&c.provideInfos[i]) {
+			output := internal.ProvideOutput{Type: input.Type, Name: input.Name}
+			if elemOutput, ok := lazyElemOutput(input); ok {
+				// digpro.Lazy[T] depends on T
+				output = elemOutput
+			}
+			if _, ok := outputs[output]; ok && !input.Optional && input.Group == "" {
+				dependents = append(dependents, fmt.Sprintf("%v depends on %s", c.provideInfos[i].Location(), input.String()))
+			}
+		}
+	}
+	if len(dependents) != 0 {
+		return wrapError("Remove", fmt.Errorf("the provider %v of %s is depended by other providers:\n\t%s", info.Location(), key.String(), strings.Join(dependents, "\n\t")))
+	}
+
+	removeProviders(c, []int{index})
+	for output := range outputs {
+		delete(c.propertyInjects, output)
+	}
+	c.recordCall(func(clone *ContainerWrapper) error {
+		return clone.Remove(typ, opts...)
+	})
+	return nil
+}
+
+// providerInputs return all inputs of the provider, include the fields of digpro.ResolveCyclic Struct
+func (c *ContainerWrapper) providerInputs(info *internal.ProvideInfosWrapper) []internal.ProvideInput {
+	inputs := info.ExportedInputs()
+	for _, output := range info.ExportedOutputs() {
+		if propertyInject, ok := c.propertyInjects[output]; ok && propertyInject.ResolveCyclic {
+			return append(append([]internal.ProvideInput{}, inputs...), propertyInject.Inputs...)
+		}
+	}
+	return inputs
+}
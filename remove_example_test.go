@@ -0,0 +1,16 @@
+package digpro_test
+
+import (
+	"fmt"
+
+	"github.com/rectcircle/digpro"
+)
+
+func ExampleContainerWrapper_Remove() {
+	c := digpro.New()
+	_ = c.Supply(1) // please handle error in production
+	_ = c.Remove(int(0))
+	_, err := c.Extract(int(0))
+	fmt.Println(err != nil)
+	// Output: true
+}
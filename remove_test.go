@@ -0,0 +1,195 @@
+package digpro
+
+import (
+	"strings"
+	"testing"
+
+	"go.uber.org/dig"
+)
+
+func TestContainerWrapper_Remove(t *testing.T) {
+	type args struct {
+		prepare PrepareFunc
+		typ     interface{}
+		opts    []ExtractOption
+	}
+	tests := []struct {
+		name           string
+		args           args
+		wantErr        bool
+		wantErrContain string
+		assert         func(t *testing.T, c *ContainerWrapper)
+	}{
+		{
+			name: "remove",
+			args: args{
+				prepare: func(c *ContainerWrapper) error {
+					return firstError(
+						c.Supply(1),
+						c.Supply("a"),
+					)
+				},
+				typ: int(0),
+			},
+			assert: func(t *testing.T, c *ContainerWrapper) {
+				if _, err := c.Extract(int(0)); err == nil {
+					t.Errorf("c.Extract(int) want error")
+				}
+				if _, err := c.Extract(""); err != nil {
+					t.Errorf("c.Extract(string) error = %v", err)
+				}
+				// can provide again
+				if err := c.Supply(2); err != nil {
+					t.Errorf("c.Supply() error = %v", err)
+				}
+			},
+		},
+		{
+			name: "remove by name and all outputs",
+			args: args{
+				prepare: func(c *ContainerWrapper) error {
+					return firstError(
+						c.Supply(1),
+						c.Provide(func() (int, string) { return 2, "a" }, dig.Name("a")),
+					)
+				},
+				typ:  int(0),
+				opts: []ExtractOption{ExtractByName("a")},
+			},
+			assert: func(t *testing.T, c *ContainerWrapper) {
+				if _, err := c.Extract("", ExtractByName("a")); err == nil {
+					t.Errorf("c.Extract(string) want error")
+				}
+				if i, err := c.Extract(int(0)); err != nil || i != 1 {
+					t.Errorf("c.Extract(int) = %v, %v, want 1", i, err)
+				}
+			},
+		},
+		{
+			name: "remove interface",
+			args: args{
+				prepare: func(c *ContainerWrapper) error {
+					return c.Provide(func() I1 { return &DI1{} })
+				},
+				typ: new(I1),
+			},
+			assert: func(t *testing.T, c *ContainerWrapper) {
+				if _, err := c.Extract(new(I1)); err == nil {
+					t.Errorf("c.Extract(I1) want error")
+				}
+			},
+		},
+		{
+			name: "remove depended by optional",
+			args: args{
+				prepare: func(c *ContainerWrapper) error {
+					return firstError(
+						c.Supply(1.0),
+						c.Struct(new(D4)),
+					)
+				},
+				typ: float64(0),
+			},
+			assert: func(t *testing.T, c *ContainerWrapper) {
+				if _, err := c.Extract(new(D4)); err != nil {
+					t.Errorf("c.Extract(*D4) error = %v", err)
+				}
+			},
+		},
+		{
+			name: "remove group",
+			args: args{
+				prepare: func(c *ContainerWrapper) error {
+					return firstError(
+						c.Supply(1, dig.Group("g")),
+						c.Supply(2, dig.Group("g")),
+					)
+				},
+				typ:  []int{},
+				opts: []ExtractOption{ExtractByGroup("g")},
+			},
+			assert: func(t *testing.T, c *ContainerWrapper) {
+				if is, err := c.Extract([]int{}, ExtractByGroup("g")); err != nil || len(is.([]int)) != 0 {
+					t.Errorf("c.Extract(group) = %v, %v, want empty", is, err)
+				}
+			},
+		},
+		{
+			name: "error not found",
+			args: args{
+				prepare: func(c *ContainerWrapper) error {
+					return c.Supply(1)
+				},
+				typ: "",
+			},
+			wantErr:        true,
+			wantErrContain: "[Remove] no provider of string was found",
+		},
+		{
+			name: "error has called",
+			args: args{
+				prepare: func(c *ContainerWrapper) error {
+					_ = c.Supply(1)
+					_, err := c.Extract(int(0))
+					return err
+				},
+				typ: int(0),
+			},
+			wantErr:        true,
+			wantErrContain: "has called",
+		},
+		{
+			name: "error depended",
+			args: args{
+				prepare: func(c *ContainerWrapper) error {
+					return firstError(
+						c.Supply(1),
+						c.Struct(new(lifecycleB)),
+					)
+				},
+				typ: int(0),
+			},
+			wantErr:        true,
+			wantErrContain: "depends on int",
+		},
+		{
+			name: "error depended by resolve cyclic",
+			args: args{
+				prepare: func(c *ContainerWrapper) error {
+					return firstError(
+						c.Supply(1),
+						c.Supply("a"),
+						c.Struct(new(D1), ResolveCyclic()),
+						c.Struct(new(D2), ResolveCyclic()),
+					)
+				},
+				typ: new(D2),
+			},
+			wantErr:        true,
+			wantErrContain: "depends on *digpro.D2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+			if err := tt.args.prepare(c); err != nil {
+				t.Errorf("prepare() error = %v", err)
+				return
+			}
+			err := c.Remove(tt.args.typ, tt.args.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("c.Remove() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				if !strings.Contains(err.Error(), tt.wantErrContain) {
+					t.Errorf("c.Remove() error = %v, wantErrContain %s", err, tt.wantErrContain)
+				}
+				return
+			}
+			if tt.assert != nil {
+				tt.assert(t, c)
+			}
+		})
+	}
+}
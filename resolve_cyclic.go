@@ -1,15 +1,29 @@
 package digpro
 
 import (
+	"errors"
+	"fmt"
 	"reflect"
+	"sync"
 
 	"github.com/rectcircle/digpro/internal"
 	"github.com/rectcircle/digpro/internal/digcopy"
 	"go.uber.org/dig"
 )
 
-// ResolveCyclic to resolve *digpro.ContainerWrapper.Struct cyclic dependency.
-// The option only support *digpro.ContainerWrapper.Struct and *digglobal.Struct functions.
+// ResolveCyclic to resolve *digpro.ContainerWrapper.Struct and *digpro.ContainerWrapper.Provide cyclic dependency.
+// The option only support *digpro.ContainerWrapper.Struct, *digpro.ContainerWrapper.Provide and the functions of digglobal.
+//
+// When use with Provide, the constructor must return a pointer (and an optional error), for example func(b *B) (*A, error).
+// A placeholder pointer will be provided to other constructors first,
+// and the constructor will be called when the value is invoked, then the result will be copied into the placeholder.
+// So the placeholder will be zero value when other constructors are called, and the result must be a fresh allocation which can be copied:
+// Provide return error if the result type contains the type must not be copied (e.g. sync.Mutex),
+// the constructor return error if the result is referred by the values reachable from it (e.g. self pointer, or kept by the dependencies),
+// and please don't keep the pointer returned by the constructor in other places (e.g. goroutine, callbacks or singleton), which cannot be detected.
+// For the same reason, don't register the method of the returned pointer as hook in the constructor
+// (e.g. lc.Append(digpro.Hook{OnStart: s.Start})), and digpro.ManagedLifecycle() is not supported,
+// please use Struct with digpro.ResolveCyclic() instead.
 //
 // for example
 //   type D1 struct {
@@ -55,23 +69,30 @@ type resolveCyclicOriginProvideInfoProvideOption struct {
 // resolveCyclicProvideMiddleware record all provide inputs
 func resolveCyclicProvideMiddleware(pc *provideContext) error {
 	var (
-		resolveCyclic = false
-		provideInfo   *internal.ProvideInfosWrapper
-		_opts         = make([]dig.ProvideOption, 0, len(pc.opts))
+		resolveCyclic            = false
+		resolveCyclicConstructor = false
+		provideInfo              *internal.ProvideInfosWrapper
+		_opts                    = make([]dig.ProvideOption, 0, len(pc.opts))
 	)
 	for _, opt := range pc.opts {
 		if o, ok := opt.(resolveCyclicOriginProvideInfoProvideOption); ok {
 			provideInfo = o.provideInfo
 			resolveCyclic = true
+		} else if _, ok := opt.(resolveCyclicProvideOption); ok {
+			resolveCyclicConstructor = true
 		} else {
 			_opts = append(_opts, opt)
 		}
 	}
 	pc.opts = _opts
+	if resolveCyclicConstructor {
+		return resolveCyclicConstructorProvide(pc)
+	}
 	err := pc.next()
 	if err != nil {
 		return err
 	}
+	pc.c.provideInfos[len(pc.c.provideInfos)-1].ResolveCyclic = resolveCyclic
 	if provideInfo == nil {
 		provideInfo = &pc.c.provideInfos[len(pc.c.provideInfos)-1]
 	}
@@ -87,19 +108,95 @@ func resolveCyclicProvideMiddleware(pc *provideContext) error {
 	return nil
 }
 
-func (c *ContainerWrapper) getLocationByOutput(input internal.ProvideOutput) *digcopy.Func {
-	containerValue := reflect.ValueOf(&c.Container).Elem()
+// isResolveCyclicConstructorType return true if the constructor of type ft return a pointer and an optional error
+func isResolveCyclicConstructorType(ft reflect.Type) bool {
+	return ft != nil && ft.Kind() == reflect.Func && (ft.NumOut() == 1 || ft.NumOut() == 2 && ft.Out(1) == internal.ErrorType) && ft.Out(0).Kind() == reflect.Ptr
+}
+
+// resolveCyclicConstructorProvide provide a placeholder constructor instead of pc.constructor,
+// pc.constructor will be called when property inject
+func resolveCyclicConstructorProvide(pc *provideContext) error {
+	ft := reflect.TypeOf(pc.constructor)
+	if ft == nil || ft.Kind() != reflect.Func {
+		return fmt.Errorf("must provide constructor function, got %v (type %v)", pc.constructor, ft)
+	}
+	if !isResolveCyclicConstructorType(ft) {
+		return fmt.Errorf("constructor should return a pointer and an optional error, when use digpro.ResolveCyclic option, but got %v", ft)
+	}
+	if internal.ApplyProvideOptions(pc.opts...).Group != "" {
+		return errors.New("cannot use digpro.ResolveCyclic() with value groups")
+	}
+	if field, ok := noCopyField(ft.Out(0).Elem(), ft.Out(0).Elem().String()); ok {
+		return fmt.Errorf("%s must not be copied (e.g. sync.Mutex), but the result of constructor is copied into placeholder when use digpro.ResolveCyclic option, please use Struct with digpro.ResolveCyclic() instead", field)
+	}
+	// get origin ProvideInfo, let dig check constructor
+	originInfo := internal.ProvideInfosWrapper{}
+	digOpts, _ := filterProvideOptionAndGetDigproOptions(pc.opts, digproProvideOptionTypeEnum...)
+	err := dig.New().Provide(pc.constructor, append([]dig.ProvideOption{dig.FillProvideInfo(&originInfo.ProvideInfo)}, digOpts...)...)
+	if err != nil {
+		return err
+	}
+	constructor := reflect.ValueOf(pc.constructor)
+	constructorPC := constructor.Pointer()
+	location := internal.ApplyProvideOptions(pc.opts...).Location
+	if location == nil {
+		pc.opts = append([]dig.ProvideOption{dig.LocationForPC(constructorPC)}, pc.opts...)
+	}
+	resultType := ft.Out(0)
+	pc.constructor = reflect.MakeFunc(reflect.FuncOf(nil, []reflect.Type{resultType}, false), func([]reflect.Value) []reflect.Value {
+		return []reflect.Value{reflect.New(resultType.Elem())}
+	}).Interface()
+	err = pc.next()
+	if err != nil {
+		return err
+	}
+	pc.c.provideInfos[len(pc.c.provideInfos)-1].ResolveCyclic = true
+	propertyInfo := internal.PropertyInfo{
+		ResolveCyclic: true,
+		Inputs:        originInfo.ExportedInputs(),
+		Constructor:   constructor,
+		ConstructorPC: constructorPC,
+	}
+	for _, output := range pc.c.provideInfos[len(pc.c.provideInfos)-1].ExportedOutputs() {
+		pc.c.propertyInjects[output] = &propertyInfo
+	}
+	pc.c.existResolveCyclicOption = true
+	return nil
+}
+
+// digProviders return the nodes which provide output in dig.Container.providers, type is []*dig.node
+func (c *ContainerWrapper) digProviders(output internal.ProvideOutput) reflect.Value {
+	return digContainerProviders(&c.Container, output)
+}
+
+// provided return true if output is provided in dig.Container
+func (c *ContainerWrapper) provided(output internal.ProvideOutput) bool {
+	return isProvided(&c.Container, output)
+}
+
+func isProvided(c *dig.Container, output internal.ProvideOutput) bool {
+	nodes := digContainerProviders(c, output)
+	return nodes.IsValid() && nodes.Len() != 0
+}
+
+// digContainerProviders return the nodes which provide output in c.providers, type is []*dig.node
+func digContainerProviders(c *dig.Container, output internal.ProvideOutput) reflect.Value {
+	containerValue := reflect.ValueOf(c).Elem()
 
 	providersValue := internal.EnsureValueExported(containerValue.FieldByName("providers")) // map[dig.key][]*dig.node
 
 	keyType := providersValue.Type().Key()
 
 	key := reflect.New(keyType).Elem()
-	internal.EnsureValueExported(key.FieldByName("t")).Set(reflect.ValueOf(input.Type))
-	internal.EnsureValueExported(key.FieldByName("name")).Set(reflect.ValueOf(input.Name))
-	internal.EnsureValueExported(key.FieldByName("group")).Set(reflect.ValueOf(input.Group))
+	internal.EnsureValueExported(key.FieldByName("t")).Set(reflect.ValueOf(output.Type))
+	internal.EnsureValueExported(key.FieldByName("name")).Set(reflect.ValueOf(output.Name))
+	internal.EnsureValueExported(key.FieldByName("group")).Set(reflect.ValueOf(output.Group))
 
-	node := providersValue.MapIndex(key)
+	return providersValue.MapIndex(key)
+}
+
+func (c *ContainerWrapper) getLocationByOutput(input internal.ProvideOutput) *digcopy.Func {
+	node := c.digProviders(input)
 	if !node.IsValid() {
 		// dead code
 		return nil
@@ -108,14 +205,7 @@ func (c *ContainerWrapper) getLocationByOutput(input internal.ProvideOutput) *di
 		// dead code
 		return nil
 	}
-	node = node.Index(0).Elem()
-	originLocation := internal.EnsureValueExported(node.FieldByName("location")).Elem()
-	location := &digcopy.Func{}
-	location.Name = originLocation.FieldByName("Name").Interface().(string)
-	location.Package = originLocation.FieldByName("Package").Interface().(string)
-	location.File = originLocation.FieldByName("File").Interface().(string)
-	location.Line = originLocation.FieldByName("Line").Interface().(int)
-	return location
+	return internal.NodeLocation(node.Index(0))
 }
 
 // doPropertyInject for arg do property inject
@@ -173,8 +263,8 @@ func (c *ContainerWrapper) doPropertyInject(arg reflect.Value, structField *refl
 				return propertyInject.Error
 			}
 		}
-		// only do property inject when enable resolve cyclic option
-		if propertyInject.ResolveCyclic {
+		// only do property inject when enable resolve cyclic option of Struct
+		if propertyInject.ResolveCyclic && !propertyInject.Constructor.IsValid() {
 			argStruct := internal.EnsureValueExported(underlyingValue(arg))
 			if argStruct.Kind() != reflect.Struct {
 				// Dead code
@@ -193,6 +283,142 @@ func (c *ContainerWrapper) doPropertyInject(arg reflect.Value, structField *refl
 			}
 		}
 	}
+	// all inputs has injected, call the constructor of Provide
+	if propertyInject.Constructor.IsValid() {
+		location := c.getLocationByOutput(key)
+		if err := c.callResolveCyclicConstructor(arg, propertyInject, location); err != nil {
+			propertyInject.Injected = false
+			propertyInject.Error = internal.WrapResolveCyclicError(err, location, &key)
+			return propertyInject.Error
+		}
+		return nil
+	}
+	// all fields has injected, call PostConstruct
+	if propertyInject.ResolveCyclic {
+		if _, err := postConstruct(arg.Interface()); err != nil {
+			propertyInject.Injected = false
+			location := c.getLocationByOutput(key)
+			err = fmt.Errorf("received non-nil error from function %v: %w", location, wrapError("Struct", err))
+			propertyInject.Error = internal.WrapResolveCyclicError(err, location, &key)
+			return propertyInject.Error
+		}
+	}
 
 	return nil
 }
+
+// callResolveCyclicConstructor call the constructor of Provide with ResolveCyclic option, and copy the result into placeholder
+func (c *ContainerWrapper) callResolveCyclicConstructor(placeholder reflect.Value, propertyInject *internal.PropertyInfo, location *digcopy.Func) error {
+	for placeholder.Kind() == reflect.Interface {
+		placeholder = placeholder.Elem()
+	}
+	constructor := propertyInject.Constructor
+	ft := constructor.Type()
+	inTypes := make([]reflect.Type, 0, ft.NumIn())
+	for i := 0; i < ft.NumIn(); i++ {
+		inTypes = append(inTypes, ft.In(i))
+	}
+	function := reflect.MakeFunc(reflect.FuncOf(inTypes, []reflect.Type{internal.ErrorType}, ft.IsVariadic()), func(args []reflect.Value) []reflect.Value {
+		var results []reflect.Value
+		if ft.IsVariadic() {
+			results = constructor.CallSlice(args)
+		} else {
+			results = constructor.Call(args)
+		}
+		if len(results) == 2 && !results[1].IsNil() {
+			return []reflect.Value{reflect.ValueOf(fmt.Errorf("received non-nil error from function %v: %w", location, results[1].Interface().(error)))}
+		}
+		if results[0].IsNil() {
+			return []reflect.Value{reflect.ValueOf(fmt.Errorf("constructor return nil %s, when use digpro.ResolveCyclic option", results[0].Type()))}
+		}
+		if referred, ok := referToValue(results[0]); ok {
+			return []reflect.Value{reflect.ValueOf(fmt.Errorf("%s refers to the result of constructor, which is copied into placeholder and then not used, when use digpro.ResolveCyclic option", referred))}
+		}
+		placeholder.Elem().Set(results[0].Elem())
+		return []reflect.Value{reflect.New(internal.ErrorType).Elem()}
+	})
+	return c.Invoke(function.Interface(), internal.LocationFixOption{PC: propertyInject.ConstructorPC})
+}
+
+var lockerType = reflect.TypeOf((*sync.Locker)(nil)).Elem()
+
+// noCopyField return the path of the field of typ (or typ itself) which must not be copied,
+// the type is treated as no copy if its pointer implements sync.Locker (e.g. sync.Mutex, sync.WaitGroup's noCopy), same as go vet copylocks
+func noCopyField(typ reflect.Type, path string) (string, bool) {
+	if reflect.PtrTo(typ).Implements(lockerType) {
+		return fmt.Sprintf("%s (type %s)", path, typ), true
+	}
+	switch typ.Kind() {
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			f := typ.Field(i)
+			if field, ok := noCopyField(f.Type, path+"."+f.Name); ok {
+				return field, true
+			}
+		}
+	case reflect.Array:
+		if typ.Len() != 0 {
+			return noCopyField(typ.Elem(), path+"[0]")
+		}
+	}
+	return "", false
+}
+
+// referToValue return the description of the value which refers to *ptr (or the fields of *ptr),
+// the values reachable from *ptr are searched, include itself (self pointer) and the dependencies which keep ptr
+func referToValue(ptr reflect.Value) (string, bool) {
+	begin := ptr.Pointer()
+	end := begin + ptr.Type().Elem().Size()
+	type visitKey struct {
+		addr uintptr
+		typ  reflect.Type
+	}
+	visited := map[visitKey]struct{}{}
+	var walk func(v reflect.Value, path string) (string, bool)
+	walk = func(v reflect.Value, path string) (string, bool) {
+		switch v.Kind() {
+		case reflect.Ptr, reflect.UnsafePointer, reflect.Slice, reflect.Map:
+			if v.IsNil() {
+				return "", false
+			}
+			if addr := v.Pointer(); addr >= begin && addr < end && v.Kind() != reflect.Map {
+				return fmt.Sprintf("%s (type %s)", path, v.Type()), true
+			}
+			key := visitKey{addr: v.Pointer(), typ: v.Type()}
+			if _, ok := visited[key]; ok {
+				return "", false
+			}
+			visited[key] = struct{}{}
+		}
+		switch v.Kind() {
+		case reflect.Ptr, reflect.Interface:
+			if !v.IsNil() {
+				return walk(v.Elem(), path)
+			}
+		case reflect.Struct:
+			for i := 0; i < v.NumField(); i++ {
+				if referred, ok := walk(v.Field(i), path+"."+v.Type().Field(i).Name); ok {
+					return referred, true
+				}
+			}
+		case reflect.Array, reflect.Slice:
+			for i := 0; i < v.Len(); i++ {
+				if referred, ok := walk(v.Index(i), fmt.Sprintf("%s[%d]", path, i)); ok {
+					return referred, true
+				}
+			}
+		case reflect.Map:
+			iter := v.MapRange()
+			for iter.Next() {
+				if referred, ok := walk(iter.Key(), path+"{key}"); ok {
+					return referred, true
+				}
+				if referred, ok := walk(iter.Value(), fmt.Sprintf("%s[%v]", path, iter.Key())); ok {
+					return referred, true
+				}
+			}
+		}
+		return "", false
+	}
+	return walk(ptr.Elem(), ptr.Type().Elem().String())
+}
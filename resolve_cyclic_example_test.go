@@ -79,3 +79,22 @@ func ExampleResolveCyclic() {
 	// DI1: {I2: {I1: ..., Value: 'a'}, Value: 1}
 	// DI2: {I1: {I2: ..., Value: 1}, Value: 'a'}
 }
+
+func ExampleResolveCyclic_provide() {
+	c := digpro.New()
+	_ = c.Supply(1) // please handle error in production
+	_ = c.Supply("a")
+	_ = c.Provide(func(d2 *D2, value int) *D1 {
+		return &D1{D2: d2, Value: value}
+	}, digpro.ResolveCyclic()) // enable resolve cyclic dependency
+	_ = c.Provide(func(d1 *D1, value string) *D2 {
+		return &D2{D1: d1, Value: value}
+	})
+	d1, _ := c.Extract(new(D1))
+	d2, _ := c.Extract(new(D2))
+	fmt.Println(d1.(*D1).String())
+	fmt.Println(d2.(*D2).String())
+	// Output:
+	// D1: {D2: {D1: ..., Value: 'a'}, Value: 1}
+	// D2: {D1: {D2: ..., Value: 1}, Value: 'a'}
+}
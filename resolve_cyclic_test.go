@@ -1,7 +1,12 @@
 package digpro
 
 import (
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/rectcircle/digpro/internal/digcopy"
 	"github.com/rectcircle/digpro/internal/tests"
@@ -595,6 +600,101 @@ func TestResolveCyclic(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "ResolveCyclic with PostConstruct",
+			args: args{
+				prepare: func(c *ContainerWrapper) error {
+					errs := []error{
+						c.Supply(1),
+						c.Supply("a"),
+						c.Struct(new(D6), ResolveCyclic()),
+						c.Struct(new(D7)),
+					}
+					for _, err := range errs {
+						if err != nil {
+							return err
+						}
+					}
+					return nil
+				},
+			},
+			prepareWantErr: false,
+			assert: func(t *testing.T, c *ContainerWrapper) {
+				d7, err := c.Extract(new(D7))
+				if err != nil {
+					t.Errorf("Extract *D7 error: %v", err)
+					return
+				}
+				want := "D6: {D7: {D6: ..., Value: 1}, Value: 'a'}"
+				if got := d7.(*D7).D6.Desc; got != want {
+					t.Errorf("Expected D6.Desc %s, got %s", want, got)
+					return
+				}
+			},
+		},
+		{
+			name: "ResolveCyclic with PostConstruct error",
+			args: args{
+				prepare: func(c *ContainerWrapper) error {
+					errs := []error{
+						c.Supply(1),
+						c.Supply(""),
+						c.Struct(new(D6), ResolveCyclic()),
+						c.Struct(new(D7)),
+					}
+					for _, err := range errs {
+						if err != nil {
+							return err
+						}
+					}
+					return nil
+				},
+			},
+			prepareWantErr: false,
+			assert: func(t *testing.T, c *ContainerWrapper) {
+				for i := 0; i < 2; i++ {
+					_, err := c.Extract(new(D6))
+					if err == nil {
+						t.Errorf("[%d] Extract *D6 want error, got nil", i)
+						return
+					}
+					if !strings.Contains(err.Error(), "[Struct] D6.Value is required") {
+						t.Errorf("[%d] Extract *D6 error = %v, want contain %s", i, err, "[Struct] D6.Value is required")
+						return
+					}
+				}
+			},
+		},
+		{
+			name: "ResolveCyclic with default",
+			args: args{
+				prepare: func(c *ContainerWrapper) error {
+					errs := []error{
+						c.Supply(2),
+						c.Struct(new(DefaultFoo), ResolveCyclic()),
+					}
+					for _, err := range errs {
+						if err != nil {
+							return err
+						}
+					}
+					return nil
+				},
+			},
+			prepareWantErr: false,
+			assert: func(t *testing.T, c *ContainerWrapper) {
+				foo, err := c.Extract(new(DefaultFoo))
+				if err != nil {
+					t.Errorf("Extract *DefaultFoo error: %v", err)
+					return
+				}
+				want := &DefaultFoo{A: 2, B: "b", C: time.Second, D: []string{"a", "b"}}
+				if !reflect.DeepEqual(foo, want) {
+					t.Errorf("Expected *DefaultFoo %#v, got %#v", want, foo)
+					return
+				}
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -611,3 +711,188 @@ func TestResolveCyclic(t *testing.T) {
 		})
 	}
 }
+
+func newD1(d2 *D2, value int) *D1 {
+	return &D1{D2: d2, Value: value}
+}
+
+func newD2(d1 *D1, value string) (*D2, error) {
+	if value == "" {
+		return nil, errors.New("value is empty")
+	}
+	return &D2{D1: d1, Value: value}, nil
+}
+
+type resolveCyclicLocked struct {
+	D2 *D2
+	mu [1]struct{ sync.Mutex }
+}
+
+type resolveCyclicSelf struct {
+	self *resolveCyclicSelf
+}
+
+type resolveCyclicRegistry struct {
+	items []*resolveCyclicItem
+}
+
+type resolveCyclicItem struct {
+	Registry *resolveCyclicRegistry
+}
+
+func TestResolveCyclic_provide(t *testing.T) {
+	tests := []struct {
+		name           string
+		prepare        PrepareFunc
+		wantPrepareErr string
+		extract        interface{}
+		want           string
+		wantErr        string
+	}{
+		{
+			name: "provide and provide",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Supply(1),
+					c.Supply("a"),
+					c.Provide(newD1, ResolveCyclic()),
+					c.Provide(newD2),
+				)
+			},
+			extract: new(D2),
+			want:    "D2: {D1: {D2: ..., Value: 1}, Value: 'a'}",
+		},
+		{
+			name: "provide and struct",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Supply(1),
+					c.Supply("a"),
+					c.Provide(newD1, ResolveCyclic()),
+					c.Struct(new(D2), ResolveCyclic()),
+				)
+			},
+			extract: new(D1),
+			want:    "D1: {D2: {D1: ..., Value: 'a'}, Value: 1}",
+		},
+		{
+			name: "provide with dig.As",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Supply(1),
+					c.Supply("a"),
+					c.Provide(func(i2 I2, value int) *DI1 { return &DI1{I2: i2, Value: value} }, dig.As(new(I1)), ResolveCyclic()),
+					c.Provide(func(i1 I1, value string) *DI2 { return &DI2{I1: i1, Value: value} }, dig.As(new(I2))),
+				)
+			},
+			extract: new(I1),
+			want:    "DI1: {I2: {I1: ..., Value: 'a'}, Value: 1}",
+		},
+		{
+			name: "error constructor failed",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Supply(1),
+					c.Supply(""),
+					c.Provide(newD1),
+					c.Provide(newD2, ResolveCyclic()),
+				)
+			},
+			extract: new(D1),
+			wantErr: "value is empty",
+		},
+		{
+			name: "error constructor return nil",
+			prepare: func(c *ContainerWrapper) error {
+				return c.Provide(func() *D1 { return nil }, ResolveCyclic())
+			},
+			extract: new(D1),
+			wantErr: "constructor return nil *digpro.D1, when use digpro.ResolveCyclic option",
+		},
+		{
+			name: "error result contains lock",
+			prepare: func(c *ContainerWrapper) error {
+				return c.Provide(func(d2 *D2) *resolveCyclicLocked { return &resolveCyclicLocked{D2: d2} }, ResolveCyclic())
+			},
+			wantPrepareErr: "digpro.resolveCyclicLocked.mu[0] (type struct { sync.Mutex }) must not be copied",
+		},
+		{
+			name: "error result refers to itself",
+			prepare: func(c *ContainerWrapper) error {
+				return c.Provide(func() *resolveCyclicSelf {
+					s := &resolveCyclicSelf{}
+					s.self = s
+					return s
+				}, ResolveCyclic())
+			},
+			extract: new(resolveCyclicSelf),
+			wantErr: "digpro.resolveCyclicSelf.self (type *digpro.resolveCyclicSelf) refers to the result of constructor",
+		},
+		{
+			name: "error result kept by dependency",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Supply(&resolveCyclicRegistry{}),
+					c.Provide(func(r *resolveCyclicRegistry) *resolveCyclicItem {
+						item := &resolveCyclicItem{Registry: r}
+						r.items = append(r.items, item)
+						return item
+					}, ResolveCyclic()),
+				)
+			},
+			extract: new(resolveCyclicItem),
+			wantErr: "digpro.resolveCyclicItem.Registry.items[0] (type *digpro.resolveCyclicItem) refers to the result of constructor",
+		},
+		{
+			name: "error not return pointer",
+			prepare: func(c *ContainerWrapper) error {
+				return c.Provide(func() D1 { return D1{} }, ResolveCyclic())
+			},
+			wantPrepareErr: "constructor should return a pointer and an optional error",
+		},
+		{
+			name: "error group",
+			prepare: func(c *ContainerWrapper) error {
+				return c.Provide(func() *D1 { return &D1{} }, dig.Group("g"), ResolveCyclic())
+			},
+			wantPrepareErr: "cannot use digpro.ResolveCyclic() with value groups",
+		},
+		{
+			name: "error not function",
+			prepare: func(c *ContainerWrapper) error {
+				return c.Provide(1, ResolveCyclic())
+			},
+			wantPrepareErr: "must provide constructor function",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+			err := tt.prepare(c)
+			if (err != nil) != (tt.wantPrepareErr != "") {
+				t.Errorf("prepare() error = %v, wantErr %v", err, tt.wantPrepareErr)
+				return
+			}
+			if err != nil {
+				if !strings.Contains(err.Error(), tt.wantPrepareErr) {
+					t.Errorf("prepare() error = %v, wantErr %v", err, tt.wantPrepareErr)
+				}
+				return
+			}
+			got, err := c.Extract(tt.extract)
+			if (err != nil) != (tt.wantErr != "") {
+				t.Errorf("c.Extract() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				if !strings.Contains(err.Error(), tt.wantErr) || !strings.Contains(err.Error(), "resolve_cyclic_test.go") {
+					t.Errorf("c.Extract() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if s := got.(interface{ String() string }).String(); s != tt.want {
+				t.Errorf("c.Extract() = %s, want %s", s, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package digpro
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+
+	"github.com/rectcircle/digpro/internal"
+	"go.uber.org/dig"
+)
+
+// Scope make a child container, which can see all providers of parent (include registered after Scope is called),
+// and can add it's own providers (support Provide, Struct, Supply, Override, ResolveCyclic and so on).
+//
+// The values provided by parent are constructed by parent and shared with parent and all children,
+// the values provided by child are isolated from parent and siblings.
+// If the child want to replace a provider of parent, please use digpro.Override() option.
+//
+// for example
+//   c := digpro.New()
+//   _ = c.Supply(1) // please handle error in production
+//   child1 := c.Scope("child1")
+//   _ = child1.Supply("a")
+//   child2 := c.Scope("child2")
+//   _ = child2.Supply(2, digpro.Override())
+//   i1, _ := child1.Extract(int(0))
+//   s1, _ := child1.Extract("")
+//   i2, _ := child2.Extract(int(0))
+//   _, err := child2.Extract("")
+//   fmt.Println(i1, s1, i2, err != nil)
+//   // Output: 1 a 2 true
+func (c *ContainerWrapper) Scope(name string) *ContainerWrapper {
+	child := New(c.digOptions...)
+	child.parent = c
+	child.name = name
+	child.bridgedOutputs = make(map[internal.ProvideOutput]struct{})
+	if pc, _, _, ok := runtime.Caller(1); ok {
+		child.scopePC = pc
+	}
+	return child
+}
+
+// syncParent provide all outputs of parent providers which have not been provided in c
+func (c *ContainerWrapper) syncParent() {
+	if c.parent == nil || c.syncingParent {
+		return
+	}
+	c.syncingParent = true
+	defer func() { c.syncingParent = false }()
+	c.parent.syncParent()
+	for ; c.parentSynced < len(c.parent.provideInfos); c.parentSynced++ {
+		info := &c.parent.provideInfos[c.parentSynced]
+		if info.Removed {
+			continue
+		}
+		for _, output := range info.ExportedOutputs() {
+			if _, ok := c.bridgedOutputs[output]; ok {
+				continue
+			}
+			c.bridgedOutputs[output] = struct{}{}
+			// if child has provided the output, the error will be ignored, child provider first
+			if err := c.provideBridge(output); err == nil {
+				c.setLastProviderKind(internal.ProviderKindBridge)
+			}
+		}
+	}
+}
+
+// provideBridge provide a constructor which extract the output from parent
+func (c *ContainerWrapper) provideBridge(output internal.ProvideOutput) error {
+	resultType := output.Type
+	opts := []dig.ProvideOption{}
+	extractOpts := []ExtractOption{}
+	if output.Group != "" {
+		resultType = reflect.SliceOf(output.Type)
+		opts = append(opts, dig.Group(output.Group+",flatten"), groupBridgeProvideOption{})
+		extractOpts = append(extractOpts, ExtractByGroup(output.Group))
+	} else if output.Name != "" {
+		opts = append(opts, dig.Name(output.Name))
+		extractOpts = append(extractOpts, ExtractByName(output.Name))
+	}
+	if c.scopePC != 0 {
+		opts = append(opts, dig.LocationForPC(c.scopePC))
+	}
+	ft := reflect.FuncOf([]reflect.Type{}, []reflect.Type{resultType, internal.ErrorType}, false)
+	fv := reflect.MakeFunc(ft, func([]reflect.Value) []reflect.Value {
+		ptr := reflect.New(resultType)
+		errValue := reflect.New(internal.ErrorType).Elem()
+		if err := c.parent.Invoke(internal.MakeExtractFunc(ptr.Interface(), extractOpts...)); err != nil {
+			err = c.fixBridgeErrLocation(err, output)
+			errValue = reflect.ValueOf(fmt.Errorf("[Scope %s] extract %s from parent error: %w", c.name, output.String(), err))
+		}
+		return []reflect.Value{ptr.Elem(), errValue}
+	})
+	return newProvideContext(c, fv.Interface(), opts).next()
+}
+
+// fixBridgeErrLocation replace the location of the error (reflect.makeFuncStub) by the location of the parent provider,
+// use the location of Scope if the output is a value group (which has many providers) or the provider is not found
+func (c *ContainerWrapper) fixBridgeErrLocation(err error, output internal.ProvideOutput) error {
+	if output.Group == "" {
+		if location := c.parent.getLocationByOutput(output); location != nil {
+			return internal.TryFixDigErrByFunc(err, location)
+		}
+	}
+	return internal.TryFixDigErr(err, c.scopePC)
+}
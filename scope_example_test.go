@@ -0,0 +1,22 @@
+package digpro_test
+
+import (
+	"fmt"
+
+	"github.com/rectcircle/digpro"
+)
+
+func ExampleContainerWrapper_Scope() {
+	c := digpro.New()
+	_ = c.Supply(1) // please handle error in production
+	child1 := c.Scope("child1")
+	_ = child1.Supply("a")
+	child2 := c.Scope("child2")
+	_ = child2.Supply(2, digpro.Override())
+	i1, _ := child1.Extract(int(0))
+	s1, _ := child1.Extract("")
+	i2, _ := child2.Extract(int(0))
+	_, err := child2.Extract("")
+	fmt.Println(i1, s1, i2, err != nil)
+	// Output: 1 a 2 true
+}
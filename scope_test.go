@@ -0,0 +1,298 @@
+package digpro
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"go.uber.org/dig"
+)
+
+func TestContainerWrapper_Scope(t *testing.T) {
+	type args struct {
+		prepareParent PrepareFunc
+		prepareChild  PrepareFunc
+	}
+	tests := []struct {
+		name              string
+		args              args
+		wantErr           bool
+		extract           interface{}
+		extractOptions    []ExtractOption
+		wantExtractErr    bool
+		wantExtractErrMsg string
+		want              interface{}
+	}{
+		{
+			name: "see parent provider",
+			args: args{
+				prepareParent: func(c *ContainerWrapper) error {
+					return c.Supply(1)
+				},
+				prepareChild: func(c *ContainerWrapper) error {
+					return c.Supply("a")
+				},
+			},
+			extract: int(0),
+			want:    1,
+		},
+		{
+			name: "see parent provider registered after scope",
+			args: args{
+				prepareChild: func(c *ContainerWrapper) error {
+					return c.parent.Supply(1, dig.Name("late"))
+				},
+			},
+			extract:        int(0),
+			extractOptions: []ExtractOption{ExtractByName("late")},
+			want:           1,
+		},
+		{
+			name: "child struct depend on parent",
+			args: args{
+				prepareParent: func(c *ContainerWrapper) error {
+					return c.Supply(1)
+				},
+				prepareChild: func(c *ContainerWrapper) error {
+					return c.Struct(new(lifecycleB))
+				},
+			},
+			extract: new(lifecycleB),
+			want:    &lifecycleB{Value: 1},
+		},
+		{
+			name: "child override parent struct",
+			args: args{
+				prepareParent: func(c *ContainerWrapper) error {
+					return firstError(
+						c.Supply(1),
+						c.Struct(new(lifecycleB)),
+					)
+				},
+				prepareChild: func(c *ContainerWrapper) error {
+					return c.Supply(&lifecycleB{Value: 2}, Override())
+				},
+			},
+			extract: new(lifecycleB),
+			want:    &lifecycleB{Value: 2},
+		},
+		{
+			name: "child override parent provider success",
+			args: args{
+				prepareParent: func(c *ContainerWrapper) error {
+					return firstError(
+						c.Supply(1),
+						c.Struct(new(lifecycleB)),
+					)
+				},
+				prepareChild: func(c *ContainerWrapper) error {
+					return c.Supply(2, Override())
+				},
+			},
+			extract: int(0),
+			want:    2,
+		},
+		{
+			name: "error child provide conflict with parent without override",
+			args: args{
+				prepareParent: func(c *ContainerWrapper) error {
+					return c.Supply(1)
+				},
+				prepareChild: func(c *ContainerWrapper) error {
+					return c.Supply(2)
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "child provider first when parent provide later",
+			args: args{
+				prepareChild: func(c *ContainerWrapper) error {
+					return firstError(
+						c.Supply(2),
+						c.parent.Supply(1),
+					)
+				},
+			},
+			extract: int(0),
+			want:    2,
+		},
+		{
+			name: "group merge parent and child",
+			args: args{
+				prepareParent: func(c *ContainerWrapper) error {
+					return firstError(
+						c.Supply("a", dig.Group("g")),
+						c.Supply("b", dig.Group("g")),
+					)
+				},
+				prepareChild: func(c *ContainerWrapper) error {
+					return c.Supply("c", dig.Group("g"))
+				},
+			},
+			extract:        []string{},
+			extractOptions: []ExtractOption{ExtractByGroup("g")},
+			want:           []string{"a", "b", "c"},
+		},
+		{
+			name: "child resolve cyclic",
+			args: args{
+				prepareParent: func(c *ContainerWrapper) error {
+					return firstError(
+						c.Supply(1),
+						c.Supply("a"),
+					)
+				},
+				prepareChild: func(c *ContainerWrapper) error {
+					return firstError(
+						c.Struct(new(D1), ResolveCyclic()),
+						c.Struct(new(D2), ResolveCyclic()),
+					)
+				},
+			},
+			extract: new(D1),
+			want:    "D1: {D2: {D1: ..., Value: 'a'}, Value: 1}",
+		},
+		{
+			name: "error parent constructor failed",
+			args: args{
+				prepareParent: func(c *ContainerWrapper) error {
+					return c.Struct(new(lifecycleB))
+				},
+			},
+			extract:           new(lifecycleB),
+			wantExtractErr:    true,
+			wantExtractErrMsg: "[Scope child] extract *digpro.lifecycleB from parent error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := New()
+			if tt.args.prepareParent != nil {
+				if err := tt.args.prepareParent(parent); err != nil {
+					t.Errorf("prepareParent() error = %v", err)
+					return
+				}
+			}
+			child := parent.Scope("child")
+			var err error
+			if tt.args.prepareChild != nil {
+				err = tt.args.prepareChild(child)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("prepareChild() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			got, err := child.Extract(tt.extract, tt.extractOptions...)
+			if (err != nil) != tt.wantExtractErr {
+				t.Errorf("child.Extract() error = %v, wantErr %v", err, tt.wantExtractErr)
+				return
+			}
+			if err != nil {
+				if !strings.Contains(err.Error(), tt.wantExtractErrMsg) {
+					t.Errorf("child.Extract() error = %v, wantErrMsg %s", err, tt.wantExtractErrMsg)
+				}
+				return
+			}
+			if s, ok := got.([]string); ok {
+				sort.Strings(s)
+			}
+			if stringer, ok := got.(interface{ String() string }); ok {
+				got = stringer.String()
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("child.Extract() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContainerWrapper_Scope_isolation(t *testing.T) {
+	parent := New()
+	calledCount := 0
+	if err := parent.Provide(func() *lifecycleB {
+		calledCount++
+		return &lifecycleB{Value: calledCount}
+	}); err != nil {
+		t.Errorf("parent.Provide() error = %v", err)
+		return
+	}
+	child1 := parent.Scope("child1")
+	child2 := parent.Scope("child2")
+	if err := firstError(
+		child1.Struct(new(lifecycleA)),
+		child2.Struct(new(lifecycleA)),
+	); err != nil {
+		t.Errorf("child.Struct() error = %v", err)
+		return
+	}
+	a1, err1 := child1.Extract(new(lifecycleA))
+	a2, err2 := child2.Extract(new(lifecycleA))
+	b, err := parent.Extract(new(lifecycleB))
+	if err := firstError(err1, err2, err); err != nil {
+		t.Errorf("Extract() error = %v", err)
+		return
+	}
+	if a1 == a2 {
+		t.Errorf("values of child1 and child2 should be isolated")
+	}
+	if a1.(*lifecycleA).B != b || a2.(*lifecycleA).B != b {
+		t.Errorf("values of parent should be shared")
+	}
+	if calledCount != 1 {
+		t.Errorf("parent constructor called %d times, want 1", calledCount)
+	}
+	if _, err := parent.Extract(new(lifecycleA)); err == nil {
+		t.Errorf("parent should not see providers of child")
+	}
+}
+
+func TestContainerWrapper_Scope_errorLocation(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(parent *ContainerWrapper) error
+		extract func(child *ContainerWrapper) error
+	}{
+		{
+			name: "missing dependencies of parent provider",
+			prepare: func(parent *ContainerWrapper) error {
+				return parent.Provide(func(s string) int { return len(s) })
+			},
+			extract: func(child *ContainerWrapper) error {
+				_, err := child.Extract(int(0))
+				return err
+			},
+		},
+		{
+			name: "missing dependencies of parent group provider",
+			prepare: func(parent *ContainerWrapper) error {
+				return parent.Provide(func(s string) int { return len(s) }, dig.Group("ints"))
+			},
+			extract: func(child *ContainerWrapper) error {
+				_, err := child.Extract([]int{}, ExtractByGroup("ints"))
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := New()
+			if err := tt.prepare(parent); err != nil {
+				t.Errorf("prepare() error = %v", err)
+				return
+			}
+			err := tt.extract(parent.Scope("child"))
+			if err == nil {
+				t.Errorf("extract() error = nil, want error")
+				return
+			}
+			if strings.Contains(err.Error(), "reflect") || !strings.Contains(err.Error(), "from parent error: could not build arguments for function \"github.com/rectcircle/digpro\".TestContainerWrapper_Scope_errorLocation") {
+				t.Errorf("extract() error = %v, want location of parent provider or Scope", err)
+			}
+		})
+	}
+}
@@ -16,11 +16,19 @@ func wrapError(prefix string, err error) error {
 	return fmt.Errorf("[%s] %s", prefix, err.Error())
 }
 
-func _struct(structOrStructPtr interface{}, resolveCyclic bool) interface{} {
+// _struct make the constructor of structOrStructPtr, provided see copyFromParameterObject
+func _struct(structOrStructPtr interface{}, resolveCyclic bool, provided func(output internal.ProvideOutput) bool) interface{} {
 	parameterObjectType, fieldMapping, err := makeParameterObjectType(structOrStructPtr, resolveCyclic)
 	if err != nil {
 		return wrapError("Struct", err)
 	}
+	defaults, err := makeStructDefaults(structOrStructPtr)
+	if err != nil {
+		return wrapError("Struct", err)
+	}
+	if len(defaults) != 0 && provided == nil {
+		return wrapError("Struct", errors.New("default tag is not supported without container, please use *digpro.ContainerWrapper.Struct"))
+	}
 
 	parameterTypes := []reflect.Type{parameterObjectType}
 	structOrStructPtrType := reflect.TypeOf(structOrStructPtr)
@@ -29,7 +37,11 @@ func _struct(structOrStructPtr interface{}, resolveCyclic bool) interface{} {
 	ft := reflect.FuncOf(parameterTypes, returnTypes, false)
 	fv := reflect.MakeFunc(ft, func(p []reflect.Value) []reflect.Value {
 		// copy from parameter to injectedObject and return
-		injectedObject, err := copyFromParameterObject(structOrStructPtr, p[0], fieldMapping)
+		injectedObject, err := copyFromParameterObject(structOrStructPtr, p[0], fieldMapping, defaults, provided)
+		if err == nil && !resolveCyclic {
+			// when resolve cyclic, PostConstruct will be called after property inject
+			injectedObject, err = postConstruct(injectedObject)
+		}
 		errValue := reflect.ValueOf(wrapError("Struct", err))
 		var injectedObjectValue reflect.Value
 		if err == nil {
@@ -45,6 +57,42 @@ func _struct(structOrStructPtr interface{}, resolveCyclic bool) interface{} {
 	return fv.Interface()
 }
 
+// PostConstructor can be implemented by the struct (or struct pointer) registered by Struct,
+// PostConstruct will be called after all fields injected, for validate or finish initialization.
+// If PostConstruct return error, the constructor will return the error.
+//
+// for example
+//   type Foo struct {
+//   	A string
+//   }
+//   func (f *Foo) PostConstruct() error {
+//   	if f.A == "" {
+//   		return errors.New("Foo.A is required")
+//   	}
+//   	return nil
+//   }
+type PostConstructor interface {
+	PostConstruct() error
+}
+
+// postConstruct call PostConstruct if injectedObject or it's pointer implements PostConstructor
+func postConstruct(injectedObject interface{}) (interface{}, error) {
+	if pc, ok := injectedObject.(PostConstructor); ok {
+		return injectedObject, pc.PostConstruct()
+	}
+	value := reflect.ValueOf(injectedObject)
+	if value.Kind() != reflect.Struct {
+		return injectedObject, nil
+	}
+	ptrValue := reflect.New(value.Type())
+	ptrValue.Elem().Set(value)
+	if pc, ok := ptrValue.Interface().(PostConstructor); ok {
+		err := pc.PostConstruct()
+		return ptrValue.Elem().Interface(), err
+	}
+	return injectedObject, nil
+}
+
 // Struct make a struct constructor.
 //
 // support all dig tags and `digpro:"ignore"`
@@ -56,6 +104,11 @@ func _struct(structOrStructPtr interface{}, resolveCyclic bool) interface{} {
 //   	D string   `digpro:"ignore"`  // ignore this field
 //   }
 //
+// default tag is not supported (the constructor cannot know whether the optional dependency is provided),
+// please use *digpro.ContainerWrapper.Struct
+//
+// if the struct or struct pointer implements digpro.PostConstructor, PostConstruct() will be called after all fields injected
+//
 // for example
 //   type Foo struct {
 //   	A       string
@@ -101,7 +154,7 @@ func _struct(structOrStructPtr interface{}, resolveCyclic bool) interface{} {
 //   fmt.Printf("%#v", foo)
 //   // Output: digpro_test.Foo{A:"a", B:1, C:2, private:true, ignore:3}
 func Struct(structOrStructPtr interface{}) interface{} {
-	return _struct(structOrStructPtr, false)
+	return _struct(structOrStructPtr, false, nil)
 }
 
 // Struct make a struct constructor.
@@ -113,8 +166,13 @@ func Struct(structOrStructPtr interface{}) interface{} {
 //   	B []string `group:"b"`
 //   	C bool     `optional:"true"`
 //   	D string   `digpro:"ignore"`  // ignore this field
+//   	E int      `optional:"true" default:"1"` // if the optional dependency is not provided, use default value (a provided zero value is kept)
 //   }
 //
+// default tag support bool, int*, uint*, float*, string, time.Duration and slice of these (comma-separated)
+//
+// if the struct or struct pointer implements digpro.PostConstructor, PostConstruct() will be called after all fields injected
+//
 // for example
 //   type Foo struct {
 //   	A       string
@@ -164,10 +222,7 @@ func (c *ContainerWrapper) Struct(structOrStructPtr interface{}, opts ...dig.Pro
 	originOpts, digproProvideOption := filterProvideOptionAndGetDigproOptions(opts, digproProvideOptionTypeEnum...)
 	opts, _ = filterProvideOptionAndGetDigproOptions(opts, resolveCyclicProvideOptionType, locationFixOptionType)
 	resolveCyclic := digproProvideOption.enableResolveCyclic
-	callSkip := digproProvideOption.locationFixCallSkip
-	if callSkip == 0 {
-		callSkip = 3
-	}
+	locationFix := digproProvideOption.locationFix(3)
 
 	// check structOrStructPtr must be ptr
 	if resolveCyclic && reflect.TypeOf(structOrStructPtr).Kind() != reflect.Ptr {
@@ -176,7 +231,7 @@ func (c *ContainerWrapper) Struct(structOrStructPtr interface{}, opts ...dig.Pro
 
 	// check err and get provideInfo
 	tmpC := New()
-	err := internal.ProvideWithLocationForPC(tmpC.Provide, callSkip, _struct(structOrStructPtr, false), originOpts...)
+	err := internal.ProvideWithLocationFix(tmpC.Provide, locationFix, _struct(structOrStructPtr, false, tmpC.provided), originOpts...)
 	if err != nil {
 		return err
 	}
@@ -187,11 +242,16 @@ func (c *ContainerWrapper) Struct(structOrStructPtr interface{}, opts ...dig.Pro
 	}
 
 	// do call provide
-	provide := _struct(structOrStructPtr, resolveCyclic)
-	err = internal.ProvideWithLocationForPC(c.Provide, callSkip, provide, opts...)
+	provide := _struct(structOrStructPtr, resolveCyclic, c.provided)
+	err = internal.ProvideWithLocationFix(c.Provide, locationFix, provide, opts...)
 	if err != nil {
 		return err
 	}
+	c.setLastProviderKind(internal.ProviderKindStruct)
+	// the constructor depends on c, see digpro.ContainerWrapper.Clone
+	c.setLastRegistrationRebuild(func(clone *ContainerWrapper) interface{} {
+		return _struct(structOrStructPtr, resolveCyclic, clone.provided)
+	})
 	// record has ResolveCyclic option
 	if resolveCyclic {
 		c.existResolveCyclicOption = true
@@ -4,6 +4,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/rectcircle/digpro/internal/tests"
 	"go.uber.org/dig"
@@ -26,6 +27,8 @@ var testStructData = []struct {
 	wantOpts              []ExtractOption
 	wantExtractErr        bool
 	wantExtractErrContain string
+	// the error of Struct (without container), if it is different from ContainerWrapper.Struct
+	wantStructErrContain string
 }{
 	{
 		name: "error nil",
@@ -136,6 +139,96 @@ var testStructData = []struct {
 			C: []string{"c", "c"},
 		},
 	},
+	{
+		name: "post construct struct ptr",
+		args: testStructArgs{
+			prepare: tests.ProviderSet(
+				tests.ProviderOne(Supply(1)),
+			),
+			structOrStructPtr: new(PostConstructFoo),
+		},
+		wantErr: false,
+		want:    &PostConstructFoo{A: 1, B: 2},
+	},
+	{
+		name: "post construct struct",
+		args: testStructArgs{
+			prepare: tests.ProviderSet(
+				tests.ProviderOne(Supply(1)),
+			),
+			structOrStructPtr: PostConstructFoo{},
+		},
+		wantErr: false,
+		want:    PostConstructFoo{A: 1, B: 2},
+	},
+	{
+		name: "error post construct",
+		args: testStructArgs{
+			prepare: tests.ProviderSet(
+				tests.ProviderOne(Supply(-1)),
+			),
+			structOrStructPtr: new(PostConstructFoo),
+		},
+		wantErr:               false,
+		want:                  new(PostConstructFoo),
+		wantExtractErr:        true,
+		wantExtractErrContain: "[Struct] PostConstructFoo.A must >= 0",
+	},
+	{
+		name: "default missing optional",
+		args: testStructArgs{
+			structOrStructPtr: new(DefaultFoo),
+		},
+		wantErr:              false,
+		wantStructErrContain: "default tag is not supported without container",
+		want: &DefaultFoo{
+			A: 1,
+			B: "b",
+			C: time.Second,
+			D: []string{"a", "b"},
+		},
+	},
+	{
+		name: "default exist optional",
+		args: testStructArgs{
+			prepare: tests.ProviderSet(
+				tests.ProviderOne(Supply(2)),
+				tests.ProviderOne(Supply("bb"), dig.Name("b")),
+				tests.ProviderOne(Supply([]string{"c"})),
+				tests.ProviderOne(Supply(true)),
+			),
+			structOrStructPtr: DefaultFoo{},
+		},
+		wantErr:              false,
+		wantStructErrContain: "default tag is not supported without container",
+		want: DefaultFoo{
+			A: 2,
+			B: "bb",
+			C: time.Second,
+			D: []string{"c"},
+			E: true,
+		},
+	},
+	{
+		name: "error default invalid",
+		args: testStructArgs{
+			structOrStructPtr: struct {
+				A int `optional:"true" default:"a"`
+			}{},
+		},
+		wantErr:        true,
+		wantErrContain: `default value "a" is invalid`,
+	},
+	{
+		name: "error default not optional",
+		args: testStructArgs{
+			structOrStructPtr: struct {
+				A int `default:"1"`
+			}{},
+		},
+		wantErr:        true,
+		wantErrContain: "default tag only support optional field",
+	},
 }
 
 func TestStruct(t *testing.T) {
@@ -168,6 +261,12 @@ func TestStruct(t *testing.T) {
 				}
 			}
 			err := c.Provide(Struct(tt.args.structOrStructPtr), tt.args.opts...)
+			if tt.wantStructErrContain != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantStructErrContain) {
+					t.Errorf("c.Provide(Struct(structOrStructPtr), opts...) error = %v, want contain = %s", err, tt.wantStructErrContain)
+				}
+				return
+			}
 			if (err != nil) != tt.wantErr {
 				t.Errorf("c.Provide(Struct(structOrStructPtr), opts...) error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -238,3 +337,60 @@ func TestContainerWrapper_Struct(t *testing.T) {
 		})
 	}
 }
+
+func TestContainerWrapper_Struct_defaultProvidedZero(t *testing.T) {
+	want := DefaultFoo{A: 0, B: "", C: time.Second, D: []string{"a", "b"}}
+	prepare := func(c *ContainerWrapper) error {
+		return firstError(
+			c.Supply(0),
+			c.Supply("", dig.Name("b")),
+		)
+	}
+	tests := []struct {
+		name    string
+		extract func(c *ContainerWrapper) (interface{}, error)
+	}{
+		{
+			name: "struct",
+			extract: func(c *ContainerWrapper) (interface{}, error) {
+				if err := c.Struct(DefaultFoo{}); err != nil {
+					return nil, err
+				}
+				return c.Extract(DefaultFoo{})
+			},
+		},
+		{
+			name: "extract into",
+			extract: func(c *ContainerWrapper) (interface{}, error) {
+				got := DefaultFoo{}
+				err := c.ExtractInto(&got)
+				return got, err
+			},
+		},
+		{
+			name: "dig extract into",
+			extract: func(c *ContainerWrapper) (interface{}, error) {
+				got := DefaultFoo{}
+				err := ExtractInto(c.Unwrap(), &got)
+				return got, err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+			if err := prepare(c); err != nil {
+				t.Errorf("prepare() error = %v", err)
+				return
+			}
+			got, err := tt.extract(c)
+			if err != nil {
+				t.Errorf("extract() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("extract() = %#v, want %#v", got, want)
+			}
+		})
+	}
+}
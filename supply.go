@@ -58,9 +58,11 @@ func Supply(value interface{}) interface{} {
 //   // Output: a
 func (c *ContainerWrapper) Supply(value interface{}, opts ...dig.ProvideOption) error {
 	filteredOpts, digproOptsResult := filterProvideOptionAndGetDigproOptions(opts, locationFixOptionType)
-	callSkip := digproOptsResult.locationFixCallSkip
-	if callSkip == 0 {
-		callSkip = 3
+	locationFix := digproOptsResult.locationFix(3)
+	err := internal.ProvideWithLocationFix(c.Provide, locationFix, Supply(value), filteredOpts...)
+	if err != nil {
+		return err
 	}
-	return internal.ProvideWithLocationForPC(c.Provide, callSkip, Supply(value), filteredOpts...)
+	c.setLastProviderKind(internal.ProviderKindSupply)
+	return nil
 }
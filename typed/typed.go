@@ -0,0 +1,144 @@
+//go:build go1.18
+// +build go1.18
+
+// Package typed provides a type-safe generic API for digpro,
+// which work with *digpro.ContainerWrapper, *dig.Container and digglobal (by digglobal.Container()).
+//
+// for example
+//   c := digpro.New()
+//   _ = typed.Supply(c, 1) // please handle error in production
+//   i, _ := typed.Extract[int](c)
+//   fmt.Println(i + 1)
+//   // Output: 2
+package typed
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/rectcircle/digpro"
+	"github.com/rectcircle/digpro/internal"
+	"go.uber.org/dig"
+)
+
+// Container is implemented by *digpro.ContainerWrapper and *dig.Container
+type Container interface {
+	Provide(constructor interface{}, opts ...dig.ProvideOption) error
+	Invoke(function interface{}, opts ...dig.InvokeOption) error
+}
+
+// structProvider is implemented by *digpro.ContainerWrapper
+type structProvider interface {
+	Struct(structOrStructPtr interface{}, opts ...dig.ProvideOption) error
+}
+
+// extractor is implemented by *digpro.ContainerWrapper
+type extractor interface {
+	Extract(typ interface{}, opts ...digpro.ExtractOption) (interface{}, error)
+}
+
+func extract[T any](c Container, callSkip int, opts ...digpro.ExtractOption) (T, error) {
+	var value T
+	if e, ok := c.(extractor); ok && internal.ApplyExtractOptions(opts...).Group != "" {
+		// *digpro.ContainerWrapper support digpro.ExtractGroupOrdered() and extract value group into map[string]T
+		v, err := e.Extract(value, append(opts, internal.LocationFixOption{CallSkip: callSkip + 1})...)
+		if err != nil {
+			return value, err
+		}
+		return v.(T), nil
+	}
+	f := internal.MakeExtractFunc(&value, opts...)
+	if err, ok := f.(error); ok {
+		return value, err
+	}
+	err := internal.WrapErrorWithLocationForPC(callSkip, func(uintptr) error { return c.Invoke(f) })
+	return value, err
+}
+
+// Extract a value of type T from container, T can be any type (include interface),
+// and if use digpro.ExtractByGroup, T should be a slice of member type
+// (or map[string]MemberType for *digpro.ContainerWrapper, see digpro.GroupMemberName).
+//
+// for example
+//   c := digpro.New()
+//   _ = typed.Supply[io.Writer](c, os.Stdout) // please handle error in production
+//   w, _ := typed.Extract[io.Writer](c)
+//   fmt.Fprintln(w, "hello")
+//   // Output: hello
+func Extract[T any](c Container, opts ...digpro.ExtractOption) (T, error) {
+	return extract[T](c, 3, opts...)
+}
+
+// MustExtract like Extract, but panic if has error
+func MustExtract[T any](c Container, opts ...digpro.ExtractOption) T {
+	value, err := extract[T](c, 3, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+// ExtractOptional like Extract, but return zero value and false if T is not provided.
+//
+// for example
+//   c := digpro.New()
+//   i, found, _ := typed.ExtractOptional[int](c)
+//   fmt.Println(i, found)
+//   // Output: 0 false
+func ExtractOptional[T any](c Container, opts ...digpro.ExtractOption) (T, bool, error) {
+	value, err := extract[T](c, 3, opts...)
+	if internal.IsDigErrMissingDependencies(err) {
+		var zero T
+		return zero, false, nil
+	}
+	return value, err == nil, err
+}
+
+// Supply a value as type T into container, T can be a interface type implemented by value.
+// Support all options of *digpro.ContainerWrapper.Supply when c is *digpro.ContainerWrapper (e.g. digpro.Override()).
+//
+// for example
+//   c := digpro.New()
+//   _ = typed.Supply[io.Writer](c, os.Stdout) // please handle error in production
+//   // equals to
+//   // c.Provide(func() io.Writer { return os.Stdout })
+func Supply[T any](c Container, value T, opts ...dig.ProvideOption) error {
+	ft := reflect.FuncOf([]reflect.Type{}, []reflect.Type{reflect.TypeOf(&value).Elem()}, false)
+	fv := reflect.MakeFunc(ft, func([]reflect.Value) []reflect.Value {
+		return []reflect.Value{reflect.ValueOf(&value).Elem()}
+	})
+	if _, ok := c.(*digpro.ContainerWrapper); ok {
+		// record as a Supply, see digpro.ProviderDescriptor.Kind
+		opts = append([]dig.ProvideOption{internal.ProviderKindOption{Kind: internal.ProviderKindSupply}}, opts...)
+	}
+	return internal.ProvideWithLocationForPC(c.Provide, 3, fv.Interface(), opts...)
+}
+
+// Struct register a struct (T is a struct type) or struct pointer (T is a pointer of struct type) into container,
+// the fields will be injected, same as *digpro.ContainerWrapper.Struct.
+// Support all options of *digpro.ContainerWrapper.Struct when c is *digpro.ContainerWrapper (e.g. digpro.ResolveCyclic()),
+// and the default tag is only supported when c is *digpro.ContainerWrapper.
+//
+// for example
+//   c := digpro.New()
+//   _ = typed.Supply(c, "a") // please handle error in production
+//   _ = typed.Struct[*Foo](c)
+//   foo, _ := typed.Extract[*Foo](c)
+//   fmt.Println(foo.A)
+//   // Output: a
+func Struct[T any](c Container, opts ...dig.ProvideOption) error {
+	typ := reflect.TypeOf(new(T)).Elem()
+	var structOrStructPtr interface{}
+	switch {
+	case typ.Kind() == reflect.Struct:
+		structOrStructPtr = reflect.New(typ).Elem().Interface()
+	case typ.Kind() == reflect.Ptr && typ.Elem().Kind() == reflect.Struct:
+		structOrStructPtr = reflect.New(typ.Elem()).Interface()
+	default:
+		return fmt.Errorf("[Struct] T want struct or struct pointer, but got %s", typ)
+	}
+	if sp, ok := c.(structProvider); ok {
+		return sp.Struct(structOrStructPtr, append([]dig.ProvideOption{internal.LocationFixOption{CallSkip: 4}}, opts...)...)
+	}
+	return internal.ProvideWithLocationForPC(c.Provide, 3, digpro.Struct(structOrStructPtr), opts...)
+}
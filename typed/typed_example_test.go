@@ -0,0 +1,56 @@
+//go:build go1.18
+// +build go1.18
+
+package typed_test
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rectcircle/digpro"
+	"github.com/rectcircle/digpro/digglobal"
+	"github.com/rectcircle/digpro/typed"
+)
+
+type Foo struct {
+	A string
+}
+
+func Example() {
+	c := digpro.New()
+	_ = typed.Supply(c, 1) // please handle error in production
+	i, _ := typed.Extract[int](c)
+	fmt.Println(i + 1)
+	// Output: 2
+}
+
+func ExampleExtract() {
+	c := digpro.New()
+	_ = typed.Supply[io.Writer](c, os.Stdout) // please handle error in production
+	w, _ := typed.Extract[io.Writer](c)
+	fmt.Fprintln(w, "hello")
+	// Output: hello
+}
+
+func ExampleStruct() {
+	c := digpro.New()
+	_ = typed.Supply(c, "a") // please handle error in production
+	_ = typed.Struct[*Foo](c)
+	foo, _ := typed.Extract[*Foo](c)
+	fmt.Println(foo.A)
+	// Output: a
+}
+
+func ExampleMustExtract() {
+	digglobal.Supply("global")
+	fmt.Println(typed.MustExtract[string](digglobal.Container()))
+	// Output: global
+}
+
+func ExampleExtractOptional() {
+	c := digpro.New()
+	i, found, _ := typed.ExtractOptional[int](c)
+	fmt.Println(i, found)
+	// Output: 0 false
+}
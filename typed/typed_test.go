@@ -0,0 +1,271 @@
+//go:build go1.18
+// +build go1.18
+
+package typed
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rectcircle/digpro"
+	"github.com/rectcircle/digpro/internal/tests"
+	"go.uber.org/dig"
+)
+
+type Foo struct {
+	A string
+	B int `name:"b"`
+}
+
+type DefaultFoo struct {
+	A int `optional:"true" default:"1"`
+}
+
+type Stringer interface{ String() string }
+
+func (f *Foo) String() string {
+	return fmt.Sprintf("Foo{A: %s, B: %d}", f.A, f.B)
+}
+
+func TestTyped(t *testing.T) {
+	tests := []struct {
+		name           string
+		newContainer   func() Container
+		prepare        func(c Container) error
+		extract        func(c Container) (interface{}, error)
+		wantErr        bool
+		wantErrContain string
+		want           interface{}
+	}{
+		{
+			name:         "supply and extract",
+			newContainer: func() Container { return digpro.New() },
+			prepare: func(c Container) error {
+				return Supply(c, 1)
+			},
+			extract: func(c Container) (interface{}, error) { return Extract[int](c) },
+			want:    1,
+		},
+		{
+			name:         "supply interface",
+			newContainer: func() Container { return digpro.New() },
+			prepare: func(c Container) error {
+				return Supply[Stringer](c, &Foo{A: "a"})
+			},
+			extract: func(c Container) (interface{}, error) {
+				s, err := Extract[Stringer](c)
+				if err != nil {
+					return nil, err
+				}
+				return s.String(), nil
+			},
+			want: "Foo{A: a, B: 0}",
+		},
+		{
+			name:         "struct ptr",
+			newContainer: func() Container { return digpro.New() },
+			prepare: func(c Container) error {
+				return firstError(
+					Supply(c, "a"),
+					Supply(c, 1, dig.Name("b")),
+					Struct[*Foo](c),
+				)
+			},
+			extract: func(c Container) (interface{}, error) { return Extract[*Foo](c) },
+			want:    &Foo{A: "a", B: 1},
+		},
+		{
+			name:         "struct with dig.Container",
+			newContainer: func() Container { return dig.New() },
+			prepare: func(c Container) error {
+				return firstError(
+					Supply(c, "a"),
+					Supply(c, 1, dig.Name("b")),
+					Struct[Foo](c),
+				)
+			},
+			extract: func(c Container) (interface{}, error) { return Extract[Foo](c) },
+			want:    Foo{A: "a", B: 1},
+		},
+		{
+			name:         "struct default keep provided zero",
+			newContainer: func() Container { return digpro.New() },
+			prepare: func(c Container) error {
+				return firstError(
+					Supply(c, 0),
+					Struct[DefaultFoo](c),
+				)
+			},
+			extract: func(c Container) (interface{}, error) { return Extract[DefaultFoo](c) },
+			want:    DefaultFoo{A: 0},
+		},
+		{
+			name:         "error struct default with dig.Container",
+			newContainer: func() Container { return dig.New() },
+			prepare: func(c Container) error {
+				return Struct[DefaultFoo](c)
+			},
+			wantErr:        true,
+			wantErrContain: "default tag is not supported without container",
+		},
+		{
+			name:         "override",
+			newContainer: func() Container { return digpro.New() },
+			prepare: func(c Container) error {
+				return firstError(
+					Supply(c, 1),
+					Supply(c, 2, digpro.Override()),
+				)
+			},
+			extract: func(c Container) (interface{}, error) { return Extract[int](c) },
+			want:    2,
+		},
+		{
+			name:         "group",
+			newContainer: func() Container { return digpro.New() },
+			prepare: func(c Container) error {
+				return firstError(
+					Supply(c, 1, dig.Group("g")),
+					Supply(c, 1, dig.Group("g")),
+				)
+			},
+			extract: func(c Container) (interface{}, error) { return Extract[[]int](c, digpro.ExtractByGroup("g")) },
+			want:    []int{1, 1},
+		},
+		{
+			name:         "group ordered",
+			newContainer: func() Container { return digpro.New() },
+			prepare: func(c Container) error {
+				return firstError(
+					Supply(c, 1, dig.Group("g")),
+					Supply(c, 2, dig.Group("g"), digpro.GroupPriority(1)),
+					Supply(c, 3, dig.Group("g")),
+				)
+			},
+			extract: func(c Container) (interface{}, error) {
+				return Extract[[]int](c, digpro.ExtractByGroup("g"), digpro.ExtractGroupOrdered())
+			},
+			want: []int{2, 1, 3},
+		},
+		{
+			name:         "group map",
+			newContainer: func() Container { return digpro.New() },
+			prepare: func(c Container) error {
+				return firstError(
+					Supply(c, 1, dig.Group("g"), digpro.GroupMemberName("a")),
+					Supply(c, 2, dig.Group("g"), digpro.GroupMemberName("b")),
+				)
+			},
+			extract: func(c Container) (interface{}, error) {
+				return Extract[map[string]int](c, digpro.ExtractByGroup("g"))
+			},
+			want: map[string]int{"a": 1, "b": 2},
+		},
+		{
+			name:         "error group with location",
+			newContainer: func() Container { return digpro.New() },
+			prepare: func(c Container) error {
+				return c.Provide(func(s string) int { return 1 }, dig.Group("g"))
+			},
+			extract: func(c Container) (interface{}, error) {
+				return Extract[map[string]int](c, digpro.ExtractByGroup("g"))
+			},
+			wantErr:        true,
+			wantErrContain: tests.GetSelfSourceCodeFilePath(),
+		},
+		{
+			name:         "error struct not struct",
+			newContainer: func() Container { return digpro.New() },
+			prepare: func(c Container) error {
+				return Struct[int](c)
+			},
+			wantErr:        true,
+			wantErrContain: "[Struct] T want struct or struct pointer, but got int",
+		},
+		{
+			name:         "error extract with location",
+			newContainer: func() Container { return digpro.New() },
+			prepare:      func(c Container) error { return nil },
+			extract:      func(c Container) (interface{}, error) { return Extract[int](c) },
+			wantErr:      true,
+			// location is the caller of Extract
+			wantErrContain: tests.GetSelfSourceCodeFilePath(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.newContainer()
+			err := tt.prepare(c)
+			var got interface{}
+			if err == nil {
+				got, err = tt.extract(c)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				if !strings.Contains(err.Error(), tt.wantErrContain) {
+					t.Errorf("error = %v, wantErrContain %s", err, tt.wantErrContain)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMustExtract(t *testing.T) {
+	c := digpro.New()
+	if err := Supply(c, "a"); err != nil {
+		t.Errorf("Supply() error = %v", err)
+		return
+	}
+	if got := MustExtract[string](c); got != "a" {
+		t.Errorf("MustExtract() = %s, want a", got)
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("MustExtract() want panic")
+		}
+	}()
+	MustExtract[int](c)
+}
+
+func firstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestSupply_providerKind(t *testing.T) {
+	c := digpro.New()
+	if err := Supply(c, 1); err != nil {
+		t.Errorf("Supply() error = %v", err)
+		return
+	}
+	if err := Supply(c, 2, digpro.Override()); err != nil {
+		t.Errorf("Supply() error = %v", err)
+		return
+	}
+	for _, p := range c.Providers() {
+		if p.Kind != digpro.ProviderKindSupply {
+			t.Errorf("c.Providers()[%d].Kind = %s, want %s", p.ID, p.Kind, digpro.ProviderKindSupply)
+		}
+	}
+	buf := strings.Builder{}
+	if err := c.VisualizeMermaid(&buf, digpro.GraphHideSupply()); err != nil {
+		t.Errorf("c.VisualizeMermaid() error = %v", err)
+		return
+	}
+	if got := buf.String(); got != "graph TD\n" {
+		t.Errorf("c.VisualizeMermaid() = %s, want empty graph", got)
+	}
+}
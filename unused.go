@@ -0,0 +1,89 @@
+package digpro
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/rectcircle/digpro/internal"
+)
+
+// isRootOutput return true if the output can be extracted by root type
+func isRootOutput(root reflect.Type, output internal.ProvideOutput) bool {
+	if output.Group != "" {
+		return root.Kind() == reflect.Slice && root.Elem() == output.Type
+	}
+	return root == output.Type
+}
+
+// rootProviders return the providers (not removed) which can be extracted by root, in registration order
+func (c *ContainerWrapper) rootProviders(root interface{}) []int {
+	rootType := internal.ExtractTypeOf(root)
+	result := []int{}
+	for i := range c.provideInfos {
+		if c.provideInfos[i].Removed {
+			continue
+		}
+		for _, output := range c.provideInfos[i].ExportedOutputs() {
+			if isRootOutput(rootType, output) {
+				result = append(result, i)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// UnusedProviders return the providers whose outputs are unreachable from roots, in registration order.
+// root is same as the typ argument of Extract (e.g. new(Interface), new(StructPtr), []T{} for value group),
+// the providers of root type with any name are treated as used.
+// the element looks like `Struct *pkg.Foo at "pkg".init.0 (/path/to/file.go:10)`.
+//
+// for example
+//   c := digpro.New()
+//   _ = c.Supply(1) // please handle error in production
+//   _ = c.Supply("a")
+//   _ = c.Provide(func(i int) float64 { return float64(i) })
+//   fmt.Println(len(c.UnusedProviders(float64(0))))
+//   // Output: 1
+func (c *ContainerWrapper) UnusedProviders(roots ...interface{}) []string {
+	c.syncParent()
+	graph := c.dependencyGraph(true)
+	used := make(map[int]struct{})
+	queue := []int{}
+	for _, root := range roots {
+		if root == nil {
+			continue
+		}
+		for _, i := range c.rootProviders(root) {
+			if _, ok := used[i]; !ok {
+				used[i] = struct{}{}
+				queue = append(queue, i)
+			}
+		}
+	}
+	for len(queue) != 0 {
+		from := queue[0]
+		queue = queue[1:]
+		for _, edge := range graph[from] {
+			if _, ok := used[edge.to]; !ok {
+				used[edge.to] = struct{}{}
+				queue = append(queue, edge.to)
+			}
+		}
+	}
+	result := []string{}
+	for i := range c.provideInfos {
+		info := &c.provideInfos[i]
+		if _, ok := used[i]; ok || info.Removed || info.Kind == internal.ProviderKindBridge {
+			continue
+		}
+		outputs := info.ExportedOutputs()
+		outputStrings := make([]string, 0, len(outputs))
+		for j := range outputs {
+			outputStrings = append(outputStrings, outputs[j].String())
+		}
+		result = append(result, fmt.Sprintf("%s %s at %v", info.Kind, strings.Join(outputStrings, ", "), info.Location()))
+	}
+	return result
+}
@@ -0,0 +1,19 @@
+package digpro_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rectcircle/digpro"
+)
+
+func ExampleContainerWrapper_UnusedProviders() {
+	c := digpro.New()
+	_ = c.Supply(1) // please handle error in production
+	_ = c.Supply("a")
+	_ = c.Provide(func(i int) float64 { return float64(i) })
+	for _, provider := range c.UnusedProviders(float64(0)) {
+		fmt.Println(strings.Split(provider, " at ")[0])
+	}
+	// Output: Supply string
+}
@@ -0,0 +1,128 @@
+package digpro
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.uber.org/dig"
+)
+
+func TestContainerWrapper_UnusedProviders(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare PrepareFunc
+		scope   bool
+		roots   []interface{}
+		want    []string // prefix of result
+	}{
+		{
+			name: "no roots",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Supply(1),
+					c.Provide(func(i int) string { return "a" }),
+				)
+			},
+			want: []string{"Supply int at", "Provide string at"},
+		},
+		{
+			name: "transitive",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Supply(1),
+					c.Supply("a"),
+					c.Supply(true),
+					c.Struct(new(D1), ResolveCyclic()),
+					c.Struct(new(D2)),
+				)
+			},
+			roots: []interface{}{new(D1)},
+			want:  []string{"Supply bool at"},
+		},
+		{
+			name: "name and interface",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Supply(1, dig.Name("a")),
+					c.Supply(2),
+					c.Supply(&D1{}, dig.As(new(fmt.Stringer))),
+					c.Provide(func(in struct {
+						dig.In
+						I int `name:"a"`
+					}, s fmt.Stringer) string {
+						return s.String()
+					}),
+				)
+			},
+			roots: []interface{}{""},
+			want:  []string{"Supply int at"},
+		},
+		{
+			name: "value group",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Supply(1),
+					c.Provide(func(i int) string { return "a" }, dig.Group("g")),
+					c.Supply(true),
+				)
+			},
+			roots: []interface{}{[]string{}},
+			want:  []string{"Supply bool at"},
+		},
+		{
+			name: "overridden and nil root",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Supply(1),
+					c.Supply(2, Override()),
+					c.Supply("a"),
+				)
+			},
+			roots: []interface{}{nil, int(0)},
+			want:  []string{"Supply string at"},
+		},
+		{
+			name: "scope",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.parent.Supply(1),
+					c.parent.Supply(true),
+					c.Provide(func(i int) string { return "a" }),
+					c.Supply(1.0),
+				)
+			},
+			scope: true,
+			roots: []interface{}{""},
+			want:  []string{"Supply float64 at"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+			if tt.scope {
+				c = c.Scope("child")
+			}
+			if err := tt.prepare(c); err != nil {
+				t.Errorf("prepare() error = %v", err)
+				return
+			}
+			got := c.UnusedProviders(tt.roots...)
+			gotPrefixes := make([]string, 0, len(got))
+			for i, s := range got {
+				if !strings.Contains(s, "unused_test.go") {
+					t.Errorf("c.UnusedProviders()[%d] = %s, want contain location", i, s)
+				}
+				if i < len(tt.want) && strings.HasPrefix(s, tt.want[i]) {
+					gotPrefixes = append(gotPrefixes, tt.want[i])
+				} else {
+					gotPrefixes = append(gotPrefixes, s)
+				}
+			}
+			if !reflect.DeepEqual(gotPrefixes, tt.want) {
+				t.Errorf("c.UnusedProviders() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package digpro
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/rectcircle/digpro/internal"
+)
+
+// stronglyConnectedComponents return the component id of the providers (only the providers which keep(i) is true)
+// reachable from roots, by Tarjan's algorithm
+func stronglyConnectedComponents(graph map[int][]dependencyEdge, roots []int, keep func(i int) bool) map[int]int {
+	var (
+		index     = make(map[int]int)
+		lowLink   = make(map[int]int)
+		onStack   = make(map[int]bool)
+		stack     = []int{}
+		component = make(map[int]int)
+		count     = 0
+	)
+	var connect func(v int)
+	connect = func(v int) {
+		index[v] = len(index)
+		lowLink[v] = index[v]
+		stack = append(stack, v)
+		onStack[v] = true
+		for _, edge := range graph[v] {
+			w := edge.to
+			if !keep(w) {
+				continue
+			}
+			if _, ok := index[w]; !ok {
+				connect(w)
+				if lowLink[w] < lowLink[v] {
+					lowLink[v] = lowLink[w]
+				}
+			} else if onStack[w] && index[w] < lowLink[v] {
+				lowLink[v] = index[w]
+			}
+		}
+		if lowLink[v] != index[v] {
+			return
+		}
+		for {
+			w := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[w] = false
+			component[w] = count
+			if w == v {
+				break
+			}
+		}
+		count++
+	}
+	for _, root := range roots {
+		if _, ok := index[root]; !ok && keep(root) {
+			connect(root)
+		}
+	}
+	return component
+}
+
+// hasCycle return true if i is in a cycle, that is the component of i has more than one provider or i depends on itself
+func hasCycle(graph map[int][]dependencyEdge, component map[int]int, i int) bool {
+	for _, edge := range graph[i] {
+		if c, ok := component[edge.to]; ok && c == component[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// dependencyCycles return all elementary cycles of graph, every cycle starts at its smallest provider index.
+// the graph is split into strongly connected components first, only the providers in cycles are searched
+// by Johnson's algorithm, so that the time is linear to the size of acyclic graph.
+func dependencyCycles(graph map[int][]dependencyEdge, size int) [][]dependencyEdge {
+	cycles := [][]dependencyEdge{}
+	all := make([]int, 0, size)
+	for i := 0; i < size; i++ {
+		all = append(all, i)
+	}
+	components := stronglyConnectedComponents(graph, all, func(int) bool { return true })
+	for start := 0; start < size; start++ {
+		if !hasCycle(graph, components, start) {
+			continue
+		}
+		// the cycles through start only contain the providers >= start in the same component
+		component := stronglyConnectedComponents(graph, []int{start}, func(i int) bool {
+			return i >= start && components[i] == components[start]
+		})
+		inComponent := func(i int) bool {
+			c, ok := component[i]
+			return ok && c == component[start]
+		}
+		path := []dependencyEdge{}
+		blocked := make(map[int]bool)
+		blockedBy := make(map[int]map[int]struct{})
+		var unblock func(v int)
+		unblock = func(v int) {
+			blocked[v] = false
+			for w := range blockedBy[v] {
+				delete(blockedBy[v], w)
+				if blocked[w] {
+					unblock(w)
+				}
+			}
+		}
+		var walk func(from int) bool
+		walk = func(from int) bool {
+			found := false
+			blocked[from] = true
+			// the multiple edges between two providers are treated as one
+			visited := make(map[int]struct{})
+			for _, edge := range graph[from] {
+				if _, ok := visited[edge.to]; ok || !inComponent(edge.to) {
+					continue
+				}
+				visited[edge.to] = struct{}{}
+				if edge.to == start {
+					cycle := append(append([]dependencyEdge{}, path...), edge)
+					cycles = append(cycles, cycle)
+					found = true
+					continue
+				}
+				if blocked[edge.to] {
+					continue
+				}
+				path = append(path, edge)
+				if walk(edge.to) {
+					found = true
+				}
+				path = path[:len(path)-1]
+			}
+			if found {
+				unblock(from)
+				return true
+			}
+			// from can not reach start now, until one of the providers it depends on is unblocked
+			for to := range visited {
+				if blockedBy[to] == nil {
+					blockedBy[to] = make(map[int]struct{})
+				}
+				blockedBy[to][from] = struct{}{}
+			}
+			return false
+		}
+		walk(start)
+	}
+	return cycles
+}
+
+// Validate check the dependency graph of all providers, and report every cycle with the locations of providers.
+// the fields of digpro.ResolveCyclic Struct and digpro.Lazy inputs are not considered as cycle.
+//
+// dig check cycle when Provide by default, but if dig.DeferAcyclicVerification() is used,
+// the cycle will be reported when Invoke. Validate can be used in CI to catch cycles without running the app.
+//
+// for example
+//   c := digpro.New(dig.DeferAcyclicVerification())
+//   _ = c.Struct(new(A)) // A depends on *B, please handle error in production
+//   _ = c.Struct(new(B)) // B depends on *A
+//   err := c.Validate()
+//   fmt.Println(err != nil)
+//   // Output: true
+func (c *ContainerWrapper) Validate() error {
+	c.syncParent()
+	// the fields of digpro.ResolveCyclic Struct and digpro.Lazy inputs are not included, because they are injected after construct
+	cycles := dependencyCycles(c.dependencyGraph(false), len(c.provideInfos))
+	var errs internal.MultiError
+	for _, cycle := range cycles {
+		errs = internal.AppendError(errs, wrapError("Validate", c.cycleError(cycle)))
+	}
+	return errs.ErrorOrNil()
+}
+
+// cycleError describe the cycle and the providers which can use digpro.ResolveCyclic() to fix it
+func (c *ContainerWrapper) cycleError(cycle []dependencyEdge) error {
+	lines := make([]string, 0, len(cycle))
+	candidates := []string{}
+	for i, edge := range cycle {
+		info := &c.provideInfos[edge.from]
+		// the output of provider is the input of the previous edge
+		output := cycle[(i+len(cycle)-1)%len(cycle)].input
+		provider := fmt.Sprintf("%s %s at %v", info.Kind, output.String(), info.Location())
+		lines = append(lines, fmt.Sprintf("%s depends on %s", provider, edge.input.String()))
+		if output.Group != "" || output.Type.Kind() != reflect.Ptr {
+			continue
+		}
+		if info.Kind == internal.ProviderKindStruct || info.Kind == internal.ProviderKindProvide && isResolveCyclicConstructorType(info.ConstructorType()) {
+			candidates = append(candidates, provider)
+		}
+	}
+	msg := "cycle detected in dependency graph:\n\t" + strings.Join(lines, "\n\t")
+	if len(candidates) == 0 {
+		msg += "\n\tno Struct or Provide registration can use digpro.ResolveCyclic() to fix it, please use digpro.Lazy or refactor"
+	} else {
+		msg += "\n\tuse digpro.ResolveCyclic() option on one of the Struct or Provide registrations to fix it:\n\t\t" + strings.Join(candidates, "\n\t\t")
+	}
+	return errors.New(msg)
+}
@@ -0,0 +1,24 @@
+package digpro_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rectcircle/digpro"
+	"go.uber.org/dig"
+)
+
+func ExampleContainerWrapper_Validate() {
+	c := digpro.New(dig.DeferAcyclicVerification())
+	_ = c.Supply(1) // please handle error in production
+	_ = c.Supply("a")
+	_ = c.Struct(new(D1))
+	_ = c.Struct(new(D2))
+	err := c.Validate()
+	fmt.Println(strings.Split(err.Error(), "\n")[0])
+	_ = c.Struct(new(D1), digpro.Override(), digpro.ResolveCyclic())
+	fmt.Println(c.Validate())
+	// Output:
+	// [Validate] cycle detected in dependency graph:
+	// <nil>
+}
@@ -0,0 +1,268 @@
+package digpro
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.uber.org/dig"
+)
+
+type validateA struct {
+	B *validateB
+}
+
+type validateB struct {
+	C validateC
+}
+
+type validateC struct {
+	A *validateA
+}
+
+func TestContainerWrapper_Validate(t *testing.T) {
+	tests := []struct {
+		name           string
+		prepare        PrepareFunc
+		scope          bool
+		wantErr        bool
+		wantErrContain []string
+		wantCycles     int
+	}{
+		{
+			name: "no cycle",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Supply(1),
+					c.Supply("a"),
+					c.Struct(new(D1)),
+				)
+			},
+		},
+		{
+			name: "struct cycle",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Supply(1),
+					c.Supply("a"),
+					c.Struct(new(D1)),
+					c.Struct(new(D2)),
+				)
+			},
+			wantErr: true,
+			wantErrContain: []string{
+				"[Validate] cycle detected in dependency graph:",
+				"Struct *digpro.D1 at \"github.com/rectcircle/digpro\".TestContainerWrapper_Validate.func",
+				"validate_test.go",
+				"depends on *digpro.D2",
+				"depends on *digpro.D1",
+				"use digpro.ResolveCyclic() option on one of the Struct or Provide registrations to fix it:",
+				"Struct *digpro.D1 at",
+				"Struct *digpro.D2 at",
+			},
+			wantCycles: 1,
+		},
+		{
+			name: "struct cycle resolved",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Supply(1),
+					c.Supply("a"),
+					c.Struct(new(D1), ResolveCyclic()),
+					c.Struct(new(D2)),
+				)
+			},
+		},
+		{
+			name: "provide cycle without candidate",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Provide(func(s string) int { return 1 }),
+					c.Provide(func(i int) string { return "a" }),
+				)
+			},
+			wantErr: true,
+			wantErrContain: []string{
+				"Provide int at \"github.com/rectcircle/digpro\".TestContainerWrapper_Validate.func",
+				"depends on int",
+				"depends on string",
+				"no Struct or Provide registration can use digpro.ResolveCyclic() to fix it",
+			},
+			wantCycles: 1,
+		},
+		{
+			name: "only pointer struct is candidate",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Struct(new(validateA)),
+					c.Struct(new(validateB)),
+					c.Struct(validateC{}),
+				)
+			},
+			wantErr: true,
+			wantErrContain: []string{
+				"Struct *digpro.validateA at",
+				"Struct *digpro.validateB at",
+			},
+			wantCycles: 1,
+		},
+		{
+			name: "provide pointer is candidate",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Provide(func(b *validateB) (*validateA, error) { return &validateA{B: b}, nil }),
+					c.Provide(func(c validateC) *validateB { return &validateB{C: c} }),
+					c.Provide(func(a *validateA) validateC { return validateC{A: a} }),
+				)
+			},
+			wantErr: true,
+			wantErrContain: []string{
+				"use digpro.ResolveCyclic() option on one of the Struct or Provide registrations to fix it:\n\t\tProvide *digpro.validateA at",
+				"\n\t\tProvide *digpro.validateB at",
+			},
+			wantCycles: 1,
+		},
+		{
+			name: "provide pointer cycle resolved",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Provide(func(b *validateB) *validateA { return &validateA{B: b} }, ResolveCyclic()),
+					c.Provide(func(c validateC) *validateB { return &validateB{C: c} }),
+					c.Provide(func(a *validateA) validateC { return validateC{A: a} }),
+				)
+			},
+		},
+		{
+			name: "multiple cycles",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Provide(func(s string) int { return 1 }),
+					c.Provide(func(i int, f float64) string { return "a" }),
+					c.Provide(func(s string) float64 { return 1 }),
+				)
+			},
+			wantErr:        true,
+			wantErrContain: []string{"[0] [Validate]", "[1] [Validate]"},
+			wantCycles:     2,
+		},
+		{
+			name: "value group cycle",
+			prepare: func(c *ContainerWrapper) error {
+				type in struct {
+					dig.In
+					S []string `group:"g"`
+				}
+				return firstError(
+					c.Provide(func(i in) int { return 1 }),
+					c.Provide(func(i int) string { return "a" }, dig.Group("g")),
+				)
+			},
+			wantErr:        true,
+			wantErrContain: []string{"depends on []string[group=\"g\"]"},
+			wantCycles:     1,
+		},
+		{
+			name: "overridden provider",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Provide(func(s string) int { return 1 }),
+					c.Provide(func(i int) string { return "a" }),
+					c.Supply(1, Override()),
+				)
+			},
+		},
+		{
+			name: "scope",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.parent.Supply(1),
+					c.Provide(func(s string, i int) float64 { return 1 }),
+					c.Provide(func(f float64) string { return "a" }),
+				)
+			},
+			scope:          true,
+			wantErr:        true,
+			wantErrContain: []string{"depends on float64", "depends on string"},
+			wantCycles:     1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(dig.DeferAcyclicVerification())
+			if tt.scope {
+				c = c.Scope("child")
+			}
+			if err := tt.prepare(c); err != nil {
+				t.Errorf("prepare() error = %v", err)
+				return
+			}
+			err := c.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("c.Validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil {
+				return
+			}
+			for _, s := range tt.wantErrContain {
+				if !strings.Contains(err.Error(), s) {
+					t.Errorf("c.Validate() error = %v, wantErrContain %s", err, s)
+				}
+			}
+			if got := strings.Count(err.Error(), "[Validate]"); got != tt.wantCycles {
+				t.Errorf("c.Validate() cycles = %d, want %d", got, tt.wantCycles)
+			}
+		})
+	}
+}
+
+// provideLayeredGraph provide width * depth named int providers, every provider depends on all providers of the next layer,
+// the providers are registered dependents-first, and there are width^depth paths from the first layer to the last
+func provideLayeredGraph(c *ContainerWrapper, width, depth int) error {
+	intType := reflect.TypeOf(0)
+	for l := 0; l < depth; l++ {
+		fields := []reflect.StructField{{Name: "In", Type: reflect.TypeOf(dig.In{}), Anonymous: true}}
+		for k := 0; l+1 < depth && k < width; k++ {
+			fields = append(fields, reflect.StructField{
+				Name: fmt.Sprintf("F%d", k),
+				Type: intType,
+				Tag:  reflect.StructTag(fmt.Sprintf(`name:"%d_%d"`, l+1, k)),
+			})
+		}
+		ft := reflect.FuncOf([]reflect.Type{reflect.StructOf(fields)}, []reflect.Type{intType}, false)
+		for k := 0; k < width; k++ {
+			constructor := reflect.MakeFunc(ft, func([]reflect.Value) []reflect.Value {
+				return []reflect.Value{reflect.ValueOf(1)}
+			})
+			if err := c.Provide(constructor.Interface(), dig.Name(fmt.Sprintf("%d_%d", l, k))); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func TestContainerWrapper_Validate_largeAcyclic(t *testing.T) {
+	c := New(dig.DeferAcyclicVerification())
+	if err := provideLayeredGraph(c, 4, 30); err != nil {
+		t.Errorf("prepare error = %v", err)
+		return
+	}
+	if err := c.Validate(); err != nil {
+		t.Errorf("c.Validate() error = %v", err)
+	}
+}
+
+func BenchmarkContainerWrapper_Validate_largeAcyclic(b *testing.B) {
+	c := New(dig.DeferAcyclicVerification())
+	if err := provideLayeredGraph(c, 4, 30); err != nil {
+		b.Fatalf("prepare error = %v", err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := c.Validate(); err != nil {
+			b.Fatalf("c.Validate() error = %v", err)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package digpro
+
+import (
+	"fmt"
+
+	"github.com/rectcircle/digpro/internal"
+)
+
+// satisfied return true if the input can be provided by c, without calling any constructor
+func (c *ContainerWrapper) satisfied(input internal.ProvideInput) bool {
+	// optional input and value group (may be empty) are always satisfied
+	if input.Optional || input.Group != "" {
+		return true
+	}
+	output := internal.ProvideOutput{Type: input.Type, Name: input.Name}
+	if elemOutput, ok := lazyElemOutput(input); ok {
+		// digpro.Lazy[T] is always provided, check T instead
+		output = elemOutput
+	}
+	nodes := c.digProviders(output)
+	return nodes.IsValid() && nodes.Len() != 0
+}
+
+// Verify check the inputs of every registered provider (include the fields of digpro.ResolveCyclic Struct)
+// can be satisfied, without calling any constructor. Names, value groups, optional and dig.As are respected,
+// and the input with type digpro.Lazy[T] is satisfied when T is provided.
+// All missing dependencies are returned at once, with the locations of providers.
+//
+// for a scoped container, only the providers of itself are verified (and the outputs of parent used by it
+// are still provided by parent), please verify the parent separately.
+//
+// for example
+//   c := digpro.New()
+//   _ = c.Provide(func(s string) int { return len(s) }) // please handle error in production
+//   _ = c.Provide(func(f float64) bool { return f > 0 })
+//   err := c.Verify()
+//   fmt.Println(err != nil)
+//   // Output: true
+func (c *ContainerWrapper) Verify() error {
+	c.syncParent()
+	var errs internal.MultiError
+	for i := range c.provideInfos {
+		info := &c.provideInfos[i]
+		if info.Removed {
+			continue
+		}
+		if info.Kind == internal.ProviderKindBridge {
+			// the output may be removed from parent after the bridge is provided, see digpro.Remove
+			for _, output := range info.ExportedOutputs() {
+				if output.Group == "" && !c.parent.provided(output) {
+					errs = internal.AppendError(errs, wrapError("Verify", fmt.Errorf("%s bridged from parent at %v is removed from parent", output.String(), info.Location())))
+				}
+			}
+			continue
+		}
+		for _, input := range c.providerInputs(info) {
+			if c.satisfied(input) {
+				continue
+			}
+			errs = internal.AppendError(errs, wrapError("Verify", fmt.Errorf("missing dependency %s of %s at %v", input.String(), info.Kind, info.Location())))
+		}
+	}
+	return errs.ErrorOrNil()
+}
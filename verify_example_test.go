@@ -0,0 +1,21 @@
+package digpro_test
+
+import (
+	"fmt"
+
+	"github.com/rectcircle/digpro"
+)
+
+func ExampleContainerWrapper_Verify() {
+	c := digpro.New()
+	_ = c.Provide(func(s string) int { return len(s) }) // please handle error in production
+	_ = c.Provide(func(f float64) bool { return f > 0 })
+	err := c.Verify()
+	fmt.Println(err != nil)
+	_ = c.Supply("a")
+	_ = c.Supply(1.0)
+	fmt.Println(c.Verify())
+	// Output:
+	// true
+	// <nil>
+}
@@ -0,0 +1,184 @@
+package digpro
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.uber.org/dig"
+)
+
+type verifyFoo struct {
+	A string `name:"a"`
+	B int    `name:"b"`
+}
+
+func TestContainerWrapper_Verify(t *testing.T) {
+	tests := []struct {
+		name           string
+		prepare        PrepareFunc
+		scope          bool
+		wantErr        bool
+		wantErrContain []string
+		wantMissing    int
+	}{
+		{
+			name: "all satisfied",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Supply(1),
+					c.Supply("a"),
+					c.Struct(new(D1), ResolveCyclic()),
+					c.Struct(new(D2)),
+				)
+			},
+		},
+		{
+			name: "missing dependencies",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Provide(func(s string) int { return len(s) }),
+					c.Provide(func(f float64, i int) bool { return f > 0 }),
+				)
+			},
+			wantErr: true,
+			wantErrContain: []string{
+				"[0] [Verify] missing dependency string of Provide at \"github.com/rectcircle/digpro\".TestContainerWrapper_Verify.func",
+				"[1] [Verify] missing dependency float64 of Provide at",
+				"verify_test.go",
+			},
+			wantMissing: 2,
+		},
+		{
+			name: "name",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Supply("a"),
+					c.Supply(1, dig.Name("b")),
+					c.Struct(new(verifyFoo)),
+				)
+			},
+			wantErr:        true,
+			wantErrContain: []string{"missing dependency string[name=\"a\"] of Struct at"},
+			wantMissing:    1,
+		},
+		{
+			name: "optional and value group",
+			prepare: func(c *ContainerWrapper) error {
+				type in struct {
+					dig.In
+					S []string `group:"g"`
+					I int      `optional:"true"`
+				}
+				return c.Provide(func(i in) bool { return true })
+			},
+		},
+		{
+			name: "dig.As",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Supply(&D1{}, dig.As(new(fmt.Stringer))),
+					c.Provide(func(s fmt.Stringer) string { return s.String() }),
+				)
+			},
+		},
+		{
+			name: "resolve cyclic struct fields",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Supply(1),
+					c.Struct(new(D1), ResolveCyclic()),
+				)
+			},
+			wantErr:        true,
+			wantErrContain: []string{"missing dependency *digpro.D2 of Struct at"},
+			wantMissing:    1,
+		},
+		{
+			name: "resolve cyclic provide",
+			prepare: func(c *ContainerWrapper) error {
+				return c.Provide(func(d2 *D2) *D1 { return &D1{D2: d2} }, ResolveCyclic())
+			},
+			wantErr:        true,
+			wantErrContain: []string{"missing dependency *digpro.D2 of Provide at"},
+			wantMissing:    1,
+		},
+		{
+			name: "overridden provider",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.Provide(func(s string) int { return len(s) }),
+					c.Supply(1, Override()),
+				)
+			},
+		},
+		{
+			name: "scope",
+			prepare: func(c *ContainerWrapper) error {
+				return firstError(
+					c.parent.Supply(1),
+					c.Provide(func(i int, f float64) string { return "a" }),
+				)
+			},
+			scope:          true,
+			wantErr:        true,
+			wantErrContain: []string{"missing dependency float64 of Provide at"},
+			wantMissing:    1,
+		},
+		{
+			name: "scope parent removed",
+			prepare: func(c *ContainerWrapper) error {
+				if err := firstError(
+					c.parent.Supply(1),
+					c.parent.Supply(true),
+					c.Provide(func(i int) string { return "a" }),
+				); err != nil {
+					return err
+				}
+				// provide the bridges of int and bool
+				if err := c.Verify(); err != nil {
+					return err
+				}
+				return c.parent.Remove(int(0))
+			},
+			scope:          true,
+			wantErr:        true,
+			wantErrContain: []string{"[Verify] int bridged from parent at", "is removed from parent"},
+			wantMissing:    1,
+		},
+		{
+			name: "lifecycle",
+			prepare: func(c *ContainerWrapper) error {
+				return c.Provide(func(l Lifecycle) int { return 1 })
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+			if tt.scope {
+				c = c.Scope("child")
+			}
+			if err := tt.prepare(c); err != nil {
+				t.Errorf("prepare() error = %v", err)
+				return
+			}
+			err := c.Verify()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("c.Verify() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil {
+				return
+			}
+			for _, s := range tt.wantErrContain {
+				if !strings.Contains(err.Error(), s) {
+					t.Errorf("c.Verify() error = %v, wantErrContain %s", err, s)
+				}
+			}
+			if got := strings.Count(err.Error(), "[Verify]"); got != tt.wantMissing {
+				t.Errorf("c.Verify() missing = %d, want %d", got, tt.wantMissing)
+			}
+		})
+	}
+}
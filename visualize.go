@@ -0,0 +1,470 @@
+package digpro
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/rectcircle/digpro/internal"
+)
+
+const (
+	// GraphFormatDOT export graph as Graphviz DOT digraph, one box node per provider (labeled with kind, outputs and location)
+	// and one edge per dependency, it is rendered by digpro (not the same as dig.Visualize, which clusters the results by constructor)
+	GraphFormatDOT GraphFormat = "dot"
+	// GraphFormatMermaid export graph as Mermaid flowchart (graph TD), can be embedded in Markdown
+	GraphFormatMermaid GraphFormat = "mermaid"
+	// GraphFormatPlantUML export graph as PlantUML component diagram
+	GraphFormatPlantUML GraphFormat = "plantuml"
+)
+
+type graphOptions struct {
+	collapseGroups         bool
+	hideSupply             bool
+	highlightResolveCyclic bool
+	reverseDependents      bool
+	format                 GraphFormat
+}
+
+// GraphOption is the option of ExportGraph, VisualizeMermaid, VisualizePlantUML and VisualizeFrom,
+// only affect the graph rendering formats (DOT, Mermaid and PlantUML), the JSON format is always complete.
+type GraphOption interface {
+	applyGraphOption(opts *graphOptions)
+}
+
+type graphOptionFunc func(opts *graphOptions)
+
+func (f graphOptionFunc) applyGraphOption(opts *graphOptions) { f(opts) }
+
+// GraphCollapseGroups render the providers of a value group as one node
+func GraphCollapseGroups() GraphOption {
+	return graphOptionFunc(func(opts *graphOptions) {
+		opts.collapseGroups = true
+	})
+}
+
+// GraphHideSupply hide the providers registered by Supply, SupplyEnv and Config, which are the leaves of graph
+func GraphHideSupply() GraphOption {
+	return graphOptionFunc(func(opts *graphOptions) {
+		opts.hideSupply = true
+	})
+}
+
+// GraphHighlightResolveCyclic highlight the providers registered with digpro.ResolveCyclic(),
+// and the cycles broken by them
+func GraphHighlightResolveCyclic() GraphOption {
+	return graphOptionFunc(func(opts *graphOptions) {
+		opts.highlightResolveCyclic = true
+	})
+}
+
+// GraphReverseDependents make VisualizeFrom also render the providers which depend on the roots
+func GraphReverseDependents() GraphOption {
+	return graphOptionFunc(func(opts *graphOptions) {
+		opts.reverseDependents = true
+	})
+}
+
+// GraphRenderAs set the format of VisualizeFrom, default is GraphFormatDOT
+func GraphRenderAs(format GraphFormat) GraphOption {
+	return graphOptionFunc(func(opts *graphOptions) {
+		opts.format = format
+	})
+}
+
+// graphView is the rendering model of Graph
+type graphView struct {
+	nodes []graphViewNode
+	edges []graphViewEdge
+}
+
+type graphViewNode struct {
+	id        string
+	lines     []string
+	highlight bool
+}
+
+type graphViewEdge struct {
+	from, to  string
+	label     string
+	dotted    bool // injected after construct, by digpro.Lazy or digpro.ResolveCyclic()
+	highlight bool
+}
+
+func graphNodeID(id int) string {
+	return fmt.Sprintf("n%d", id)
+}
+
+// graphKeyLabel return the label of name and group, e.g. [name="a"]
+func graphKeyLabel(name, group string) string {
+	parts := []string{}
+	if name != "" {
+		parts = append(parts, fmt.Sprintf("name=%q", name))
+	}
+	if group != "" {
+		parts = append(parts, fmt.Sprintf("group=%q", group))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "[" + strings.Join(parts, ",") + "]"
+}
+
+// graphEdgeLabel return the label of edge, e.g. name="a",optional
+func graphEdgeLabel(edge GraphEdge) string {
+	parts := []string{}
+	if edge.Input.Name != "" {
+		parts = append(parts, fmt.Sprintf("name=%q", edge.Input.Name))
+	}
+	if edge.Input.Group != "" {
+		parts = append(parts, fmt.Sprintf("group=%q", edge.Input.Group))
+	}
+	if edge.Input.Optional {
+		parts = append(parts, "optional")
+	}
+	if edge.Lazy {
+		parts = append(parts, "lazy")
+	}
+	return strings.Join(parts, ",")
+}
+
+// resolveCyclicCycleEdges return the edges (index of graph.Edges) on the cycles which contain ResolveCyclic edge
+func resolveCyclicCycleEdges(graph Graph) map[int]struct{} {
+	adjacency := make(map[int][]dependencyEdge)
+	size := 0
+	for _, node := range graph.Nodes {
+		if node.ID+1 > size {
+			size = node.ID + 1
+		}
+	}
+	// the first edge between two providers, to map the cycle to graph.Edges
+	edgeIndex := make(map[[2]int]int)
+	for i, edge := range graph.Edges {
+		if _, ok := edgeIndex[[2]int{edge.From, edge.To}]; ok {
+			continue
+		}
+		edgeIndex[[2]int{edge.From, edge.To}] = i
+		adjacency[edge.From] = append(adjacency[edge.From], dependencyEdge{from: edge.From, to: edge.To, resolveCyclic: edge.ResolveCyclic})
+	}
+	result := make(map[int]struct{})
+	for _, cycle := range dependencyCycles(adjacency, size) {
+		broken := false
+		for _, edge := range cycle {
+			broken = broken || edge.resolveCyclic
+		}
+		if !broken {
+			continue
+		}
+		for _, edge := range cycle {
+			result[edgeIndex[[2]int{edge.from, edge.to}]] = struct{}{}
+		}
+	}
+	return result
+}
+
+// view convert graph to the rendering model by opts
+func (graph Graph) view(opts graphOptions) graphView {
+	view := graphView{}
+	nodeIDs := make(map[int]string, len(graph.Nodes))
+	groupNodes := make(map[GraphOutput]string)
+	for _, node := range graph.Nodes {
+		if opts.hideSupply && node.Kind == ProviderKindSupply.String() {
+			continue
+		}
+		// the provider only provide one value group
+		if opts.collapseGroups && len(node.Outputs) == 1 && node.Outputs[0].Group != "" {
+			key := node.Outputs[0]
+			if id, ok := groupNodes[key]; ok {
+				nodeIDs[node.ID] = id
+				continue
+			}
+			id := fmt.Sprintf("g%d", len(groupNodes))
+			groupNodes[key] = id
+			nodeIDs[node.ID] = id
+			view.nodes = append(view.nodes, graphViewNode{
+				id:    id,
+				lines: []string{"Group", "[]" + key.Type + graphKeyLabel("", key.Group)},
+			})
+			continue
+		}
+		lines := []string{node.Kind}
+		for _, output := range node.Outputs {
+			lines = append(lines, output.Type+graphKeyLabel(output.Name, output.Group))
+		}
+		if node.Location.File != "" {
+			lines = append(lines, fmt.Sprintf("%s:%d", filepath.Base(node.Location.File), node.Location.Line))
+		}
+		nodeIDs[node.ID] = graphNodeID(node.ID)
+		view.nodes = append(view.nodes, graphViewNode{
+			id:        graphNodeID(node.ID),
+			lines:     lines,
+			highlight: opts.highlightResolveCyclic && node.ResolveCyclic,
+		})
+	}
+	highlightEdges := map[int]struct{}{}
+	if opts.highlightResolveCyclic {
+		highlightEdges = resolveCyclicCycleEdges(graph)
+	}
+	visited := make(map[graphViewEdge]struct{})
+	for i, edge := range graph.Edges {
+		from, fromOK := nodeIDs[edge.From]
+		to, toOK := nodeIDs[edge.To]
+		if !fromOK || !toOK || from == to {
+			continue
+		}
+		_, highlight := highlightEdges[i]
+		viewEdge := graphViewEdge{
+			from:      from,
+			to:        to,
+			label:     graphEdgeLabel(edge),
+			dotted:    edge.Lazy || edge.ResolveCyclic,
+			highlight: highlight,
+		}
+		if _, ok := visited[viewEdge]; ok {
+			continue
+		}
+		visited[viewEdge] = struct{}{}
+		view.edges = append(view.edges, viewEdge)
+	}
+	return view
+}
+
+func (view graphView) writeDOT(w io.Writer) error {
+	escape := func(s string) string {
+		return strings.ReplaceAll(strings.ReplaceAll(s, "\\", "\\\\"), "\"", "\\\"")
+	}
+	b := strings.Builder{}
+	b.WriteString("digraph {\n")
+	b.WriteString("    node [shape=box];\n")
+	for _, node := range view.nodes {
+		lines := make([]string, 0, len(node.lines))
+		for _, line := range node.lines {
+			lines = append(lines, escape(line))
+		}
+		attrs := ""
+		if node.highlight {
+			attrs = ", style=filled, fillcolor=\"#ffdddd\", color=\"#dd3333\""
+		}
+		fmt.Fprintf(&b, "    %s [label=\"%s\"%s];\n", node.id, strings.Join(lines, "\\n"), attrs)
+	}
+	for _, edge := range view.edges {
+		attrs := []string{}
+		if edge.label != "" {
+			attrs = append(attrs, fmt.Sprintf("label=\"%s\"", escape(edge.label)))
+		}
+		if edge.dotted {
+			attrs = append(attrs, "style=dashed")
+		}
+		if edge.highlight {
+			attrs = append(attrs, "color=\"#dd3333\"")
+		}
+		if len(attrs) == 0 {
+			fmt.Fprintf(&b, "    %s -> %s;\n", edge.from, edge.to)
+		} else {
+			fmt.Fprintf(&b, "    %s -> %s [%s];\n", edge.from, edge.to, strings.Join(attrs, ", "))
+		}
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (view graphView) writeMermaid(w io.Writer) error {
+	escape := func(s string) string {
+		return strings.ReplaceAll(s, "\"", "#quot;")
+	}
+	b := strings.Builder{}
+	b.WriteString("graph TD\n")
+	highlightNodes := []string{}
+	for _, node := range view.nodes {
+		lines := make([]string, 0, len(node.lines))
+		for _, line := range node.lines {
+			lines = append(lines, escape(line))
+		}
+		fmt.Fprintf(&b, "    %s[\"%s\"]\n", node.id, strings.Join(lines, "<br/>"))
+		if node.highlight {
+			highlightNodes = append(highlightNodes, node.id)
+		}
+	}
+	highlightEdges := []string{}
+	for i, edge := range view.edges {
+		arrow := "-->"
+		if edge.dotted {
+			arrow = "-.->"
+		}
+		if edge.label != "" {
+			arrow += fmt.Sprintf("|\"%s\"|", escape(edge.label))
+		}
+		fmt.Fprintf(&b, "    %s %s %s\n", edge.from, arrow, edge.to)
+		if edge.highlight {
+			highlightEdges = append(highlightEdges, fmt.Sprint(i))
+		}
+	}
+	if len(highlightNodes) != 0 {
+		b.WriteString("    classDef resolveCyclic fill:#fdd,stroke:#d33\n")
+		fmt.Fprintf(&b, "    class %s resolveCyclic\n", strings.Join(highlightNodes, ","))
+	}
+	if len(highlightEdges) != 0 {
+		fmt.Fprintf(&b, "    linkStyle %s stroke:#d33\n", strings.Join(highlightEdges, ","))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (view graphView) writePlantUML(w io.Writer) error {
+	escape := func(s string) string {
+		return strings.ReplaceAll(s, "\"", "'")
+	}
+	b := strings.Builder{}
+	b.WriteString("@startuml\n")
+	for _, node := range view.nodes {
+		lines := make([]string, 0, len(node.lines))
+		for _, line := range node.lines {
+			lines = append(lines, escape(line))
+		}
+		color := ""
+		if node.highlight {
+			color = " #fdd"
+		}
+		fmt.Fprintf(&b, "component \"%s\" as %s%s\n", strings.Join(lines, "\\n"), node.id, color)
+	}
+	for _, edge := range view.edges {
+		line, color := "--", ""
+		if edge.dotted {
+			line = ".."
+		}
+		if edge.highlight {
+			color = "[#d33]"
+		}
+		label := ""
+		if edge.label != "" {
+			label = " : " + escape(edge.label)
+		}
+		fmt.Fprintf(&b, "%s %s%s%s> %s%s\n", edge.from, line[:1], color, line[1:], edge.to, label)
+	}
+	b.WriteString("@enduml\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// VisualizeMermaid write the dependency graph as Mermaid flowchart (graph TD) to w, see digpro.GraphOption for options.
+//
+// for example
+//   c := digpro.New()
+//   _ = c.Supply(1) // please handle error in production
+//   _ = c.Provide(func(i int) string { return fmt.Sprint(i) })
+//   _ = c.VisualizeMermaid(os.Stdout, digpro.GraphHideSupply())
+//   // Output:
+//   // graph TD
+//   //     n1["Provide<br/>string<br/>main.go:11"]
+func (c *ContainerWrapper) VisualizeMermaid(w io.Writer, opts ...GraphOption) error {
+	return c.ExportGraph(w, GraphFormatMermaid, opts...)
+}
+
+// VisualizePlantUML write the dependency graph as PlantUML component diagram to w, see digpro.GraphOption for options.
+//
+// for example
+//   c := digpro.New()
+//   _ = c.Supply(1) // please handle error in production
+//   _ = c.Provide(func(i int) string { return fmt.Sprint(i) })
+//   _ = c.VisualizePlantUML(os.Stdout, digpro.GraphHideSupply())
+//   // Output:
+//   // @startuml
+//   // component "Provide\nstring\nmain.go:11" as n1
+//   // @enduml
+func (c *ContainerWrapper) VisualizePlantUML(w io.Writer, opts ...GraphOption) error {
+	return c.ExportGraph(w, GraphFormatPlantUML, opts...)
+}
+
+// subgraph return the graph which only contain the roots and the providers reachable from roots in depth (<= 0 means unlimited),
+// and the providers depend on the roots in depth if reverse is true
+func (graph Graph) subgraph(roots []int, depth int, reverse bool) Graph {
+	dependencies := make(map[int][]int)
+	dependents := make(map[int][]int)
+	for _, edge := range graph.Edges {
+		dependencies[edge.From] = append(dependencies[edge.From], edge.To)
+		dependents[edge.To] = append(dependents[edge.To], edge.From)
+	}
+	included := make(map[int]struct{})
+	walk := func(adjacency map[int][]int) {
+		visited := make(map[int]struct{})
+		current := []int{}
+		for _, id := range roots {
+			if _, ok := visited[id]; !ok {
+				visited[id] = struct{}{}
+				current = append(current, id)
+			}
+		}
+		for level := 0; len(current) != 0 && (depth <= 0 || level < depth); level++ {
+			next := []int{}
+			for _, from := range current {
+				for _, to := range adjacency[from] {
+					if _, ok := visited[to]; !ok {
+						visited[to] = struct{}{}
+						next = append(next, to)
+					}
+				}
+			}
+			current = next
+		}
+		for id := range visited {
+			included[id] = struct{}{}
+		}
+	}
+	walk(dependencies)
+	if reverse {
+		walk(dependents)
+	}
+	result := Graph{Nodes: []GraphNode{}, Edges: []GraphEdge{}}
+	for _, node := range graph.Nodes {
+		if _, ok := included[node.ID]; ok {
+			result.Nodes = append(result.Nodes, node)
+		}
+	}
+	for _, edge := range graph.Edges {
+		_, fromOK := included[edge.From]
+		_, toOK := included[edge.To]
+		if fromOK && toOK {
+			result.Edges = append(result.Edges, edge)
+		}
+	}
+	return result
+}
+
+// VisualizeFrom write the sub dependency graph of roots to w, only contain the providers of roots and
+// their transitive dependencies in depth (<= 0 means unlimited).
+// root is same as the typ argument of Extract (e.g. new(Interface), new(StructPtr), []T{} for value group).
+// The format is GraphFormatDOT by default, can be changed by digpro.GraphRenderAs,
+// use digpro.GraphReverseDependents to also render the providers which depend on the roots, see digpro.GraphOption for other options.
+//
+// for example
+//   c := digpro.New()
+//   _ = c.Supply(1) // please handle error in production
+//   _ = c.Supply(true)
+//   _ = c.Provide(func(i int) string { return fmt.Sprint(i) })
+//   _ = c.VisualizeFrom(os.Stdout, []interface{}{""}, 0, digpro.GraphRenderAs(digpro.GraphFormatMermaid))
+//   // Output:
+//   // graph TD
+//   //     n0["Supply<br/>int<br/>main.go:10"]
+//   //     n2["Provide<br/>string<br/>main.go:12"]
+//   //     n2 --> n0
+func (c *ContainerWrapper) VisualizeFrom(w io.Writer, roots []interface{}, depth int, opts ...GraphOption) error {
+	options := graphOptions{format: GraphFormatDOT}
+	for _, opt := range opts {
+		opt.applyGraphOption(&options)
+	}
+	c.syncParent()
+	rootIDs := []int{}
+	for _, root := range roots {
+		if root == nil {
+			return wrapError("VisualizeFrom", fmt.Errorf("root must not be nil"))
+		}
+		ids := c.rootProviders(root)
+		if len(ids) == 0 {
+			return wrapError("VisualizeFrom", fmt.Errorf("missing provider of root %v", internal.ExtractTypeOf(root)))
+		}
+		rootIDs = append(rootIDs, ids...)
+	}
+	return c.graph().subgraph(rootIDs, depth, options.reverseDependents).write(w, options.format, options, "VisualizeFrom")
+}
@@ -0,0 +1,46 @@
+package digpro_test
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rectcircle/digpro"
+)
+
+func ExampleContainerWrapper_VisualizeMermaid() {
+	c := digpro.New()
+	_ = c.Supply(1) // please handle error in production
+	_ = c.Provide(func(i int) string { return fmt.Sprint(i) })
+	_ = c.VisualizeMermaid(os.Stdout)
+	// Output:
+	// graph TD
+	//     n0["Supply<br/>int<br/>visualize_example_test.go:12"]
+	//     n1["Provide<br/>string<br/>visualize_example_test.go:13"]
+	//     n1 --> n0
+}
+
+func ExampleContainerWrapper_VisualizePlantUML() {
+	c := digpro.New()
+	_ = c.Supply(1) // please handle error in production
+	_ = c.Provide(func(i int) string { return fmt.Sprint(i) })
+	_ = c.VisualizePlantUML(os.Stdout, digpro.GraphHideSupply())
+	// Output:
+	// @startuml
+	// component "Provide\nstring\nvisualize_example_test.go:25" as n1
+	// @enduml
+}
+
+func ExampleContainerWrapper_VisualizeFrom() {
+	c := digpro.New()
+	_ = c.Supply(1) // please handle error in production
+	_ = c.Supply(true)
+	_ = c.Provide(func(i int) string { return fmt.Sprint(i) })
+	_ = c.VisualizeFrom(os.Stdout, []interface{}{""}, 0)
+	// Output:
+	// digraph {
+	//     node [shape=box];
+	//     n0 [label="Supply\nint\nvisualize_example_test.go:35"];
+	//     n2 [label="Provide\nstring\nvisualize_example_test.go:37"];
+	//     n2 -> n0;
+	// }
+}
@@ -0,0 +1,296 @@
+package digpro
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"go.uber.org/dig"
+)
+
+func prepareVisualizeContainer(c *ContainerWrapper) error {
+	return firstError(
+		c.Supply(1),
+		c.Supply("a"),
+		c.Struct(new(D1), ResolveCyclic()),
+		c.Struct(new(D2)),
+		c.Provide(func(d *D1) bool { return true }, dig.Group("g")),
+		c.Provide(func(d *D2) bool { return true }, dig.Group("g")),
+		c.Provide(func(in struct {
+			dig.In
+			B []bool `group:"g"`
+			I int    `name:"i" optional:"true"`
+		}) float64 {
+			return 1
+		}),
+		c.Supply(2, dig.Name("i")),
+	)
+}
+
+func TestContainerWrapper_VisualizeMermaid(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []GraphOption
+		want       []string
+		wantNotHas []string
+	}{
+		{
+			name: "default",
+			want: []string{
+				"graph TD\n",
+				"    n0[\"Supply<br/>int<br/>visualize_test.go:",
+				"    n2[\"Struct<br/>*digpro.D1<br/>visualize_test.go:",
+				"    n4[\"Provide<br/>bool[group=#quot;g#quot;]<br/>visualize_test.go:",
+				"    n2 -.-> n3\n",
+				"    n3 --> n2\n",
+				"    n6 -->|\"group=#quot;g#quot;\"| n4\n",
+				"    n6 -->|\"group=#quot;g#quot;\"| n5\n",
+			},
+			wantNotHas: []string{"classDef", "linkStyle", "g0"},
+		},
+		{
+			name: "collapse groups",
+			opts: []GraphOption{GraphCollapseGroups()},
+			want: []string{
+				"    g0[\"Group<br/>[]bool[group=#quot;g#quot;]\"]\n",
+				"    g0 --> n2\n",
+				"    g0 --> n3\n",
+				"    n6 -->|\"group=#quot;g#quot;\"| g0\n",
+			},
+			wantNotHas: []string{"n4", "n5"},
+		},
+		{
+			name:       "hide supply",
+			opts:       []GraphOption{GraphHideSupply()},
+			want:       []string{"    n2 -.-> n3\n"},
+			wantNotHas: []string{"Supply", "n0", "n1"},
+		},
+		{
+			name: "highlight resolve cyclic",
+			opts: []GraphOption{GraphHighlightResolveCyclic()},
+			want: []string{
+				"    classDef resolveCyclic fill:#fdd,stroke:#d33\n",
+				"    class n2 resolveCyclic\n",
+				// n2 -.-> n3 is the first edge, n3 --> n2 is the third edge
+				"    linkStyle 0,2 stroke:#d33\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+			if err := prepareVisualizeContainer(c); err != nil {
+				t.Errorf("prepare() error = %v", err)
+				return
+			}
+			buf := bytes.Buffer{}
+			if err := c.VisualizeMermaid(&buf, tt.opts...); err != nil {
+				t.Errorf("c.VisualizeMermaid() error = %v", err)
+				return
+			}
+			got := buf.String()
+			for _, s := range tt.want {
+				if !strings.Contains(got, s) {
+					t.Errorf("c.VisualizeMermaid() = %s, want contain %s", got, s)
+				}
+			}
+			for _, s := range tt.wantNotHas {
+				if strings.Contains(got, s) {
+					t.Errorf("c.VisualizeMermaid() = %s, want not contain %s", got, s)
+				}
+			}
+		})
+	}
+}
+
+func TestContainerWrapper_VisualizePlantUML(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []GraphOption
+		want       []string
+		wantNotHas []string
+	}{
+		{
+			name: "default",
+			want: []string{
+				"@startuml\n",
+				"component \"Supply\\nint\\nvisualize_test.go:",
+				"component \"Provide\\nbool[group='g']\\nvisualize_test.go:",
+				"n2 ..> n3\n",
+				"n3 --> n2\n",
+				"n6 --> n4 : group='g'\n",
+				"n6 --> n7 : name='i',optional\n",
+				"@enduml\n",
+			},
+			wantNotHas: []string{"#fdd", "#d33"},
+		},
+		{
+			name: "all options",
+			opts: []GraphOption{GraphCollapseGroups(), GraphHideSupply(), GraphHighlightResolveCyclic()},
+			want: []string{
+				"component \"Group\\n[]bool[group='g']\" as g0\n",
+				"\" as n2 #fdd\n",
+				"n2 .[#d33].> n3\n",
+				"n3 -[#d33]-> n2\n",
+				"n6 --> g0 : group='g'\n",
+			},
+			wantNotHas: []string{"Supply", "n4", "n5"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+			if err := prepareVisualizeContainer(c); err != nil {
+				t.Errorf("prepare() error = %v", err)
+				return
+			}
+			buf := bytes.Buffer{}
+			if err := c.VisualizePlantUML(&buf, tt.opts...); err != nil {
+				t.Errorf("c.VisualizePlantUML() error = %v", err)
+				return
+			}
+			got := buf.String()
+			for _, s := range tt.want {
+				if !strings.Contains(got, s) {
+					t.Errorf("c.VisualizePlantUML() = %s, want contain %s", got, s)
+				}
+			}
+			for _, s := range tt.wantNotHas {
+				if strings.Contains(got, s) {
+					t.Errorf("c.VisualizePlantUML() = %s, want not contain %s", got, s)
+				}
+			}
+		})
+	}
+}
+
+func TestContainerWrapper_VisualizeFrom(t *testing.T) {
+	tests := []struct {
+		name           string
+		roots          []interface{}
+		depth          int
+		opts           []GraphOption
+		want           []string
+		wantNotHas     []string
+		wantErr        bool
+		wantErrContain string
+	}{
+		{
+			name:  "default dot",
+			roots: []interface{}{float64(0)},
+			want: []string{
+				"digraph {\n",
+				"    n0 [label=\"Supply\\nint\\nvisualize_test.go:",
+				"    n2 [label=\"Struct\\n*digpro.D1\\nvisualize_test.go:",
+				"    n2 -> n3 [style=dashed];\n",
+				"    n3 -> n2;\n",
+				"    n6 -> n4 [label=\"group=\\\"g\\\"\"];\n",
+				"    n6 -> n7 [label=\"name=\\\"i\\\",optional\"];\n",
+				"}\n",
+			},
+		},
+		{
+			name:       "depth",
+			roots:      []interface{}{float64(0)},
+			depth:      1,
+			opts:       []GraphOption{GraphRenderAs(GraphFormatMermaid)},
+			want:       []string{"graph TD\n", "    n6 -->|\"group=#quot;g#quot;\"| n4\n", "    n6 -->|\"name=#quot;i#quot;,optional\"| n7\n"},
+			wantNotHas: []string{"n0", "n1", "n2", "n3"},
+		},
+		{
+			name:       "reverse dependents",
+			roots:      []interface{}{new(D2)},
+			depth:      1,
+			opts:       []GraphOption{GraphReverseDependents(), GraphRenderAs(GraphFormatPlantUML)},
+			want:       []string{"@startuml\n", "n3 --> n1\n", "n3 --> n2\n", "n2 ..> n3\n", "n5 --> n3\n"},
+			wantNotHas: []string{"n0", "n4", "n6", "n7"},
+		},
+		{
+			name:       "value group and highlight",
+			roots:      []interface{}{[]bool{}},
+			opts:       []GraphOption{GraphCollapseGroups(), GraphHideSupply(), GraphHighlightResolveCyclic()},
+			want:       []string{"    g0 -> n2;\n", "    g0 -> n3;\n", "style=filled, fillcolor=\"#ffdddd\"", "    n2 -> n3 [style=dashed, color=\"#dd3333\"];\n"},
+			wantNotHas: []string{"n6", "Supply"},
+		},
+		{
+			name:           "error missing root",
+			roots:          []interface{}{uint(0)},
+			wantErr:        true,
+			wantErrContain: "[VisualizeFrom] missing provider of root uint",
+		},
+		{
+			name:           "error nil root",
+			roots:          []interface{}{nil},
+			wantErr:        true,
+			wantErrContain: "[VisualizeFrom] root must not be nil",
+		},
+		{
+			name:           "error unsupported format",
+			roots:          []interface{}{float64(0)},
+			opts:           []GraphOption{GraphRenderAs("unknown")},
+			wantErr:        true,
+			wantErrContain: "[VisualizeFrom] unsupported format \"unknown\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+			if err := prepareVisualizeContainer(c); err != nil {
+				t.Errorf("prepare() error = %v", err)
+				return
+			}
+			buf := bytes.Buffer{}
+			err := c.VisualizeFrom(&buf, tt.roots, tt.depth, tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("c.VisualizeFrom() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				if !strings.Contains(err.Error(), tt.wantErrContain) {
+					t.Errorf("c.VisualizeFrom() error = %v, wantErrContain %s", err, tt.wantErrContain)
+				}
+				return
+			}
+			got := buf.String()
+			for _, s := range tt.want {
+				if !strings.Contains(got, s) {
+					t.Errorf("c.VisualizeFrom() = %s, want contain %s", got, s)
+				}
+			}
+			for _, s := range tt.wantNotHas {
+				if strings.Contains(got, s) {
+					t.Errorf("c.VisualizeFrom() = %s, want not contain %s", got, s)
+				}
+			}
+		})
+	}
+}
+
+func TestContainerWrapper_VisualizeMermaid_largeAcyclic(t *testing.T) {
+	c := New(dig.DeferAcyclicVerification())
+	if err := provideLayeredGraph(c, 4, 30); err != nil {
+		t.Errorf("prepare error = %v", err)
+		return
+	}
+	buf := bytes.Buffer{}
+	if err := c.VisualizeMermaid(&buf, GraphHighlightResolveCyclic()); err != nil {
+		t.Errorf("c.VisualizeMermaid() error = %v", err)
+		return
+	}
+	if strings.Contains(buf.String(), "linkStyle") {
+		t.Errorf("c.VisualizeMermaid() = %s, want no highlighted edge", buf.String())
+	}
+}
+
+func BenchmarkContainerWrapper_VisualizeMermaid_largeAcyclic(b *testing.B) {
+	c := New(dig.DeferAcyclicVerification())
+	if err := provideLayeredGraph(c, 4, 30); err != nil {
+		b.Fatalf("prepare error = %v", err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := c.VisualizeMermaid(&bytes.Buffer{}, GraphHighlightResolveCyclic()); err != nil {
+			b.Fatalf("c.VisualizeMermaid() error = %v", err)
+		}
+	}
+}